@@ -7,6 +7,10 @@ import (
 	"errors"
 )
 
+// The unexported PBES2 ciphers desCBC, rc2CBC, cast5CBC and blowfishCBC are
+// only registered so that legacy keys can be decrypted, and are not offered
+// for encryption.
+
 type cipherWithBlock struct {
 	oid      asn1.ObjectIdentifier
 	ivSize   int
@@ -53,26 +57,180 @@ func cbcEncrypt(block cipher.Block, iv, plaintext []byte) ([]byte, error) {
 }
 
 func cbcDecrypt(block cipher.Block, iv, ciphertext []byte) ([]byte, error) {
+	if len(iv) != block.BlockSize() {
+		return nil, &MalformedInputError{Field: "IV size"}
+	}
+	if len(ciphertext) == 0 || len(ciphertext)%block.BlockSize() != 0 {
+		return nil, &MalformedInputError{Field: "encrypted data size"}
+	}
+
 	mode := cipher.NewCBCDecrypter(block, iv)
 	plaintext := make([]byte, len(ciphertext))
 	mode.CryptBlocks(plaintext, ciphertext)
 
-	// Remove padding
+	// Remove padding, an invalid padding is almost always a wrong password
 	psLen := int(plaintext[len(plaintext)-1])
 	if psLen == 0 || psLen > block.BlockSize() {
-		return nil, errors.New("pkcs8: decryption failed")
+		return nil, ErrIncorrectPassword
 	}
 
 	if len(plaintext) < psLen {
-		return nil, errors.New("pkcs8: decryption failed")
+		return nil, ErrIncorrectPassword
 	}
 
 	ps := plaintext[len(plaintext)-psLen:]
 	plaintext = plaintext[:len(plaintext)-psLen]
 
 	if !bytes.Equal(ps, bytes.Repeat([]byte{byte(psLen)}, psLen)) {
-		return nil, errors.New("pkcs8: decryption failed")
+		return nil, ErrIncorrectPassword
+	}
+
+	return plaintext, nil
+}
+
+// cipherWithStream is a stream cipher, such as RC4, that takes no IV and
+// needs no padding.
+type cipherWithStream struct {
+	oid       asn1.ObjectIdentifier
+	keySize   int
+	newStream func(key []byte) (cipher.Stream, error)
+}
+
+func (c cipherWithStream) IVSize() int {
+	return 0
+}
+
+func (c cipherWithStream) KeySize() int {
+	return c.keySize
+}
+
+func (c cipherWithStream) OID() asn1.ObjectIdentifier {
+	return c.oid
+}
+
+func (c cipherWithStream) Encrypt(key, iv, plaintext []byte) ([]byte, error) {
+	return c.crypt(key, plaintext)
+}
+
+func (c cipherWithStream) Decrypt(key, iv, ciphertext []byte) ([]byte, error) {
+	return c.crypt(key, ciphertext)
+}
+
+func (c cipherWithStream) crypt(key, in []byte) ([]byte, error) {
+	stream, err := c.newStream(key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(in))
+	stream.XORKeyStream(out, in)
+	return out, nil
+}
+
+// cipherWithAEAD is a block cipher in an authenticated mode whose parameters
+// are encoded as the GCMParameters or CCMParameters structure from RFC 5084.
+type cipherWithAEAD struct {
+	oid       asn1.ObjectIdentifier
+	nonceSize int
+	tagSize   int
+	keySize   int
+	newBlock  func(key []byte) (cipher.Block, error)
+	newMode   func(block cipher.Block, nonceSize, tagSize int) (cipher.AEAD, error)
+	// checkSizes, if set, reports whether a nonce and tag size from the
+	// parameters is supported by newMode.
+	checkSizes func(nonceSize, tagSize int) error
+}
+
+func (c cipherWithAEAD) IVSize() int {
+	return c.nonceSize
+}
+
+func (c cipherWithAEAD) KeySize() int {
+	return c.keySize
+}
+
+func (c cipherWithAEAD) OID() asn1.ObjectIdentifier {
+	return c.oid
+}
+
+func (c cipherWithAEAD) Encrypt(key, nonce, plaintext []byte) ([]byte, error) {
+	aead, err := c.newAEAD(key, len(nonce))
+	if err != nil {
+		return nil, err
 	}
+	return aead.Seal(nil, nonce, plaintext, nil), nil
+}
 
+func (c cipherWithAEAD) Decrypt(key, nonce, ciphertext []byte) ([]byte, error) {
+	aead, err := c.newAEAD(key, len(nonce))
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrIncorrectPassword
+	}
 	return plaintext, nil
 }
+
+func (c cipherWithAEAD) MarshalParameters(nonce []byte) ([]byte, error) {
+	return asn1.Marshal(aeadParameters{Nonce: nonce, ICVLen: c.tagSize})
+}
+
+func (c cipherWithAEAD) UnmarshalParameters(der []byte) (Cipher, []byte, error) {
+	var params aeadParameters
+	if err := unmarshal(der, &params); err != nil {
+		return nil, nil, err
+	}
+	if c.checkSizes != nil {
+		if err := c.checkSizes(len(params.Nonce), params.ICVLen); err != nil {
+			return nil, nil, err
+		}
+	}
+	c.nonceSize = len(params.Nonce)
+	c.tagSize = params.ICVLen
+	return c, params.Nonce, nil
+}
+
+func (c cipherWithAEAD) newAEAD(key []byte, nonceSize int) (cipher.AEAD, error) {
+	block, err := c.newBlock(key)
+	if err != nil {
+		return nil, err
+	}
+	return c.newMode(block, nonceSize, c.tagSize)
+}
+
+// aeadParameters is the GCMParameters and CCMParameters structure from RFC 5084.
+type aeadParameters struct {
+	Nonce  []byte
+	ICVLen int `asn1:"optional,default:12"`
+}
+
+const (
+	gcmStandardNonceSize = 12
+	gcmStandardTagSize   = 16
+)
+
+func newGCM(block cipher.Block, nonceSize, tagSize int) (cipher.AEAD, error) {
+	if err := checkGCMSizes(nonceSize, tagSize); err != nil {
+		return nil, err
+	}
+	if nonceSize == gcmStandardNonceSize {
+		return cipher.NewGCMWithTagSize(block, tagSize)
+	}
+	return cipher.NewGCMWithNonceSize(block, nonceSize)
+}
+
+// checkGCMSizes accepts a tag size of 12 to 16 bytes, as allowed by RFC 5084,
+// with either the standard nonce size or the standard tag size.
+func checkGCMSizes(nonceSize, tagSize int) error {
+	if tagSize < 12 || tagSize > gcmStandardTagSize {
+		return errors.New("pkcs8: invalid GCM tag size")
+	}
+	if nonceSize == 0 {
+		return errors.New("pkcs8: invalid GCM nonce size")
+	}
+	if nonceSize != gcmStandardNonceSize && tagSize != gcmStandardTagSize {
+		return errors.New("pkcs8: unsupported GCM nonce and tag size combination")
+	}
+	return nil
+}
@@ -3,15 +3,21 @@ package pkcs8
 import (
 	"crypto/aes"
 	"encoding/asn1"
+	"errors"
+
+	"github.com/nvx/pkcs8/internal/ccm"
 )
 
 var (
 	oidAES128CBC = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
 	oidAES128GCM = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 6}
+	oidAES128CCM = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 7}
 	oidAES192CBC = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
 	oidAES192GCM = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 26}
+	oidAES192CCM = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 27}
 	oidAES256CBC = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
 	oidAES256GCM = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 46}
+	oidAES256CCM = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 47}
 )
 
 func init() {
@@ -21,18 +27,27 @@ func init() {
 	RegisterCipher(oidAES128GCM, func() Cipher {
 		return AES128GCM
 	})
+	RegisterCipher(oidAES128CCM, func() Cipher {
+		return AES128CCM
+	})
 	RegisterCipher(oidAES192CBC, func() Cipher {
 		return AES192CBC
 	})
 	RegisterCipher(oidAES192GCM, func() Cipher {
 		return AES192GCM
 	})
+	RegisterCipher(oidAES192CCM, func() Cipher {
+		return AES192CCM
+	})
 	RegisterCipher(oidAES256CBC, func() Cipher {
 		return AES256CBC
 	})
 	RegisterCipher(oidAES256GCM, func() Cipher {
 		return AES256GCM
 	})
+	RegisterCipher(oidAES256CCM, func() Cipher {
+		return AES256CCM
+	})
 }
 
 // AES128CBC is the 128-bit key AES cipher in CBC mode.
@@ -44,11 +59,25 @@ var AES128CBC = cipherWithBlock{
 }
 
 // AES128GCM is the 128-bit key AES cipher in GCM mode.
-var AES128GCM = cipherWithBlock{
-	ivSize:   aes.BlockSize,
-	keySize:  16,
-	newBlock: aes.NewCipher,
-	oid:      oidAES128GCM,
+var AES128GCM = cipherWithAEAD{
+	nonceSize:  gcmStandardNonceSize,
+	tagSize:    gcmStandardTagSize,
+	keySize:    16,
+	newBlock:   aes.NewCipher,
+	newMode:    newGCM,
+	checkSizes: checkGCMSizes,
+	oid:        oidAES128GCM,
+}
+
+// AES128CCM is the 128-bit key AES cipher in CCM mode.
+var AES128CCM = cipherWithAEAD{
+	nonceSize:  ccmDefaultNonceSize,
+	tagSize:    ccmDefaultTagSize,
+	keySize:    16,
+	newBlock:   aes.NewCipher,
+	newMode:    ccm.New,
+	checkSizes: checkCCMSizes,
+	oid:        oidAES128CCM,
 }
 
 // AES192CBC is the 192-bit key AES cipher in CBC mode.
@@ -59,12 +88,26 @@ var AES192CBC = cipherWithBlock{
 	oid:      oidAES192CBC,
 }
 
-// AES192GCM is the 912-bit key AES cipher in GCM mode.
-var AES192GCM = cipherWithBlock{
-	ivSize:   aes.BlockSize,
-	keySize:  24,
-	newBlock: aes.NewCipher,
-	oid:      oidAES192GCM,
+// AES192GCM is the 192-bit key AES cipher in GCM mode.
+var AES192GCM = cipherWithAEAD{
+	nonceSize:  gcmStandardNonceSize,
+	tagSize:    gcmStandardTagSize,
+	keySize:    24,
+	newBlock:   aes.NewCipher,
+	newMode:    newGCM,
+	checkSizes: checkGCMSizes,
+	oid:        oidAES192GCM,
+}
+
+// AES192CCM is the 192-bit key AES cipher in CCM mode.
+var AES192CCM = cipherWithAEAD{
+	nonceSize:  ccmDefaultNonceSize,
+	tagSize:    ccmDefaultTagSize,
+	keySize:    24,
+	newBlock:   aes.NewCipher,
+	newMode:    ccm.New,
+	checkSizes: checkCCMSizes,
+	oid:        oidAES192CCM,
 }
 
 // AES256CBC is the 256-bit key AES cipher in CBC mode.
@@ -76,9 +119,61 @@ var AES256CBC = cipherWithBlock{
 }
 
 // AES256GCM is the 256-bit key AES cipher in GCM mode.
-var AES256GCM = cipherWithBlock{
-	ivSize:   aes.BlockSize,
-	keySize:  32,
-	newBlock: aes.NewCipher,
-	oid:      oidAES256GCM,
+var AES256GCM = cipherWithAEAD{
+	nonceSize:  gcmStandardNonceSize,
+	tagSize:    gcmStandardTagSize,
+	keySize:    32,
+	newBlock:   aes.NewCipher,
+	newMode:    newGCM,
+	checkSizes: checkGCMSizes,
+	oid:        oidAES256GCM,
+}
+
+// AES256CCM is the 256-bit key AES cipher in CCM mode.
+var AES256CCM = cipherWithAEAD{
+	nonceSize:  ccmDefaultNonceSize,
+	tagSize:    ccmDefaultTagSize,
+	keySize:    32,
+	newBlock:   aes.NewCipher,
+	newMode:    ccm.New,
+	checkSizes: checkCCMSizes,
+	oid:        oidAES256CCM,
+}
+
+const (
+	ccmDefaultNonceSize = 12
+	ccmDefaultTagSize   = 16
+)
+
+// NewAESCCM returns an AES cipher in CCM mode with the given key size (16, 24
+// or 32 bytes), nonce size (7 to 13 bytes) and tag size (4 to 16 bytes, even).
+func NewAESCCM(keySize, nonceSize, tagSize int) (Cipher, error) {
+	var c cipherWithAEAD
+	switch keySize {
+	case 16:
+		c = AES128CCM
+	case 24:
+		c = AES192CCM
+	case 32:
+		c = AES256CCM
+	default:
+		return nil, errors.New("pkcs8: invalid AES key size")
+	}
+	if err := checkCCMSizes(nonceSize, tagSize); err != nil {
+		return nil, err
+	}
+	c.nonceSize = nonceSize
+	c.tagSize = tagSize
+	return c, nil
+}
+
+// checkCCMSizes accepts the nonce and tag sizes allowed by RFC 5084.
+func checkCCMSizes(nonceSize, tagSize int) error {
+	if nonceSize < 7 || nonceSize > 13 {
+		return errors.New("pkcs8: invalid CCM nonce size")
+	}
+	if tagSize < 4 || tagSize > 16 || tagSize%2 != 0 {
+		return errors.New("pkcs8: invalid CCM tag size")
+	}
+	return nil
 }
@@ -0,0 +1,49 @@
+package pkcs8
+
+import (
+	"encoding/asn1"
+
+	"github.com/nvx/pkcs8/internal/aria"
+)
+
+var (
+	oidARIA128CBC = asn1.ObjectIdentifier{1, 2, 410, 200046, 1, 1, 2}
+	oidARIA192CBC = asn1.ObjectIdentifier{1, 2, 410, 200046, 1, 1, 7}
+	oidARIA256CBC = asn1.ObjectIdentifier{1, 2, 410, 200046, 1, 1, 12}
+)
+
+func init() {
+	RegisterCipher(oidARIA128CBC, func() Cipher {
+		return ARIA128CBC
+	})
+	RegisterCipher(oidARIA192CBC, func() Cipher {
+		return ARIA192CBC
+	})
+	RegisterCipher(oidARIA256CBC, func() Cipher {
+		return ARIA256CBC
+	})
+}
+
+// ARIA128CBC is the 128-bit key ARIA cipher in CBC mode.
+var ARIA128CBC = cipherWithBlock{
+	ivSize:   aria.BlockSize,
+	keySize:  16,
+	newBlock: aria.New,
+	oid:      oidARIA128CBC,
+}
+
+// ARIA192CBC is the 192-bit key ARIA cipher in CBC mode.
+var ARIA192CBC = cipherWithBlock{
+	ivSize:   aria.BlockSize,
+	keySize:  24,
+	newBlock: aria.New,
+	oid:      oidARIA192CBC,
+}
+
+// ARIA256CBC is the 256-bit key ARIA cipher in CBC mode.
+var ARIA256CBC = cipherWithBlock{
+	ivSize:   aria.BlockSize,
+	keySize:  32,
+	newBlock: aria.New,
+	oid:      oidARIA256CBC,
+}
@@ -0,0 +1,28 @@
+package pkcs8
+
+import (
+	"crypto/cipher"
+	"encoding/asn1"
+
+	"golang.org/x/crypto/blowfish"
+)
+
+var (
+	oidBlowfishCBC = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 3029, 1, 2}
+)
+
+func init() {
+	RegisterCipher(oidBlowfishCBC, func() Cipher {
+		return blowfishCBC
+	})
+}
+
+// blowfishCBC is the 128-bit key Blowfish cipher in CBC mode.
+var blowfishCBC = cipherWithBlock{
+	ivSize:  blowfish.BlockSize,
+	keySize: 16,
+	newBlock: func(key []byte) (cipher.Block, error) {
+		return blowfish.NewCipher(key)
+	},
+	oid: oidBlowfishCBC,
+}
@@ -0,0 +1,49 @@
+package pkcs8
+
+import (
+	"encoding/asn1"
+
+	"github.com/nvx/pkcs8/internal/camellia"
+)
+
+var (
+	oidCamellia128CBC = asn1.ObjectIdentifier{1, 2, 392, 200011, 61, 1, 1, 1, 2}
+	oidCamellia192CBC = asn1.ObjectIdentifier{1, 2, 392, 200011, 61, 1, 1, 1, 3}
+	oidCamellia256CBC = asn1.ObjectIdentifier{1, 2, 392, 200011, 61, 1, 1, 1, 4}
+)
+
+func init() {
+	RegisterCipher(oidCamellia128CBC, func() Cipher {
+		return Camellia128CBC
+	})
+	RegisterCipher(oidCamellia192CBC, func() Cipher {
+		return Camellia192CBC
+	})
+	RegisterCipher(oidCamellia256CBC, func() Cipher {
+		return Camellia256CBC
+	})
+}
+
+// Camellia128CBC is the 128-bit key Camellia cipher in CBC mode.
+var Camellia128CBC = cipherWithBlock{
+	ivSize:   camellia.BlockSize,
+	keySize:  16,
+	newBlock: camellia.New,
+	oid:      oidCamellia128CBC,
+}
+
+// Camellia192CBC is the 192-bit key Camellia cipher in CBC mode.
+var Camellia192CBC = cipherWithBlock{
+	ivSize:   camellia.BlockSize,
+	keySize:  24,
+	newBlock: camellia.New,
+	oid:      oidCamellia192CBC,
+}
+
+// Camellia256CBC is the 256-bit key Camellia cipher in CBC mode.
+var Camellia256CBC = cipherWithBlock{
+	ivSize:   camellia.BlockSize,
+	keySize:  32,
+	newBlock: camellia.New,
+	oid:      oidCamellia256CBC,
+}
@@ -0,0 +1,62 @@
+package pkcs8
+
+import (
+	"crypto/cipher"
+	"encoding/asn1"
+
+	"golang.org/x/crypto/cast5"
+)
+
+var (
+	oidCAST5CBC = asn1.ObjectIdentifier{1, 2, 840, 113533, 7, 66, 10}
+)
+
+func init() {
+	RegisterCipher(oidCAST5CBC, func() Cipher {
+		return cast5CBC
+	})
+}
+
+// cast5CBC is the 128-bit key CAST5 cipher in CBC mode.
+var cast5CBC = cast5CBCCipher{cipherWithBlock{
+	ivSize:  cast5.BlockSize,
+	keySize: cast5.KeySize,
+	newBlock: func(key []byte) (cipher.Block, error) {
+		return cast5.NewCipher(key)
+	},
+	oid: oidCAST5CBC,
+}}
+
+// cast5CBCCipher accepts both the bare IV written by OpenSSL and the
+// Parameters structure from RFC 2144 as the cipher parameters.
+type cast5CBCCipher struct {
+	cipherWithBlock
+}
+
+// cast5CBCParameters is the Parameters structure from RFC 2144.
+type cast5CBCParameters struct {
+	IV        []byte `asn1:"optional"`
+	KeyLength int
+}
+
+func (c cast5CBCCipher) MarshalParameters(iv []byte) ([]byte, error) {
+	return asn1.Marshal(iv)
+}
+
+func (c cast5CBCCipher) UnmarshalParameters(der []byte) (Cipher, []byte, error) {
+	var iv []byte
+	if err := unmarshal(der, &iv); err == nil {
+		return c, iv, nil
+	}
+	var params cast5CBCParameters
+	if err := unmarshal(der, &params); err != nil {
+		return nil, nil, err
+	}
+	if params.KeyLength != c.keySize*8 {
+		return nil, nil, &UnsupportedAlgorithmError{Kind: "cipher", OID: c.OID()}
+	}
+	if params.IV == nil {
+		params.IV = make([]byte, c.ivSize)
+	}
+	return c, params.IV, nil
+}
@@ -0,0 +1,78 @@
+package pkcs8
+
+import (
+	"encoding/asn1"
+	"errors"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+var (
+	oidChaCha20Poly1305 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 3, 18}
+)
+
+func init() {
+	RegisterCipher(oidChaCha20Poly1305, func() Cipher {
+		return ChaCha20Poly1305
+	})
+}
+
+var errChaCha20Poly1305NonceSize = errors.New("pkcs8: invalid ChaCha20-Poly1305 nonce size")
+
+// ChaCha20Poly1305 is the ChaCha20-Poly1305 AEAD cipher, as defined in RFC 8103.
+var ChaCha20Poly1305 = chaCha20Poly1305Cipher{}
+
+type chaCha20Poly1305Cipher struct{}
+
+func (chaCha20Poly1305Cipher) IVSize() int {
+	return chacha20poly1305.NonceSize
+}
+
+func (chaCha20Poly1305Cipher) KeySize() int {
+	return chacha20poly1305.KeySize
+}
+
+func (chaCha20Poly1305Cipher) OID() asn1.ObjectIdentifier {
+	return oidChaCha20Poly1305
+}
+
+func (chaCha20Poly1305Cipher) Encrypt(key, nonce, plaintext []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, errChaCha20Poly1305NonceSize
+	}
+	return aead.Seal(nil, nonce, plaintext, nil), nil
+}
+
+func (chaCha20Poly1305Cipher) Decrypt(key, nonce, ciphertext []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, errChaCha20Poly1305NonceSize
+	}
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrIncorrectPassword
+	}
+	return plaintext, nil
+}
+
+func (chaCha20Poly1305Cipher) MarshalParameters(nonce []byte) ([]byte, error) {
+	return asn1.Marshal(nonce)
+}
+
+func (c chaCha20Poly1305Cipher) UnmarshalParameters(der []byte) (Cipher, []byte, error) {
+	var nonce []byte
+	if err := unmarshal(der, &nonce); err != nil {
+		return nil, nil, err
+	}
+	if len(nonce) != chacha20poly1305.NonceSize {
+		return nil, nil, errChaCha20Poly1305NonceSize
+	}
+	return c, nonce, nil
+}
@@ -3,18 +3,49 @@ package pkcs8
 import (
 	"crypto/cipher"
 	"crypto/des" //nolint:gosec // compatibility
+	"crypto/rand"
+	"crypto/rc4" //nolint:gosec // compatibility
+	"crypto/x509/pkix"
 	"encoding/asn1"
 	"errors"
+
 	"github.com/nvx/pkcs8/internal/pkcspbkdf"
 	"github.com/nvx/pkcs8/internal/rc2"
+	"github.com/nvx/pkcs8/internal/seed"
 )
 
 var (
+	oidPBEWithSHAAnd128BitRC4        = asn1.ObjectIdentifier([]int{1, 2, 840, 113549, 1, 12, 1, 1})
+	oidPBEWithSHAAnd40BitRC4         = asn1.ObjectIdentifier([]int{1, 2, 840, 113549, 1, 12, 1, 2})
 	oidPBEWithSHAAnd3KeyTripleDESCBC = asn1.ObjectIdentifier([]int{1, 2, 840, 113549, 1, 12, 1, 3})
+	oidPBEWithSHAAnd2KeyTripleDESCBC = asn1.ObjectIdentifier([]int{1, 2, 840, 113549, 1, 12, 1, 4})
+	oidPBEWithSHAAnd128BitRC2CBC     = asn1.ObjectIdentifier([]int{1, 2, 840, 113549, 1, 12, 1, 5})
 	oidPBEWithSHAAnd40BitRC2CBC      = asn1.ObjectIdentifier([]int{1, 2, 840, 113549, 1, 12, 1, 6})
+	oidPBEWithMD2AndDESCBC           = asn1.ObjectIdentifier([]int{1, 2, 840, 113549, 1, 5, 1})
+	oidPBEWithMD2AndRC2CBC           = asn1.ObjectIdentifier([]int{1, 2, 840, 113549, 1, 5, 4})
 	oidPBEWithMD5AndDESCBC           = asn1.ObjectIdentifier([]int{1, 2, 840, 113549, 1, 5, 3})
+	oidPBEWithMD5AndRC2CBC           = asn1.ObjectIdentifier([]int{1, 2, 840, 113549, 1, 5, 6})
+	oidPBEWithSHA1AndDESCBC          = asn1.ObjectIdentifier([]int{1, 2, 840, 113549, 1, 5, 10})
+	oidPBEWithSHA1AndRC2CBC          = asn1.ObjectIdentifier([]int{1, 2, 840, 113549, 1, 5, 11})
+	oidSeedCBCWithSHA1               = asn1.ObjectIdentifier([]int{1, 2, 410, 200004, 1, 15})
 )
 
+var shaWith128BitRC4 = cipherWithStream{
+	keySize:   16,
+	newStream: newRC4,
+	oid:       oidPBEWithSHAAnd128BitRC4,
+}
+
+var shaWith40BitRC4 = cipherWithStream{
+	keySize:   5,
+	newStream: newRC4,
+	oid:       oidPBEWithSHAAnd40BitRC4,
+}
+
+func newRC4(key []byte) (cipher.Stream, error) {
+	return rc4.NewCipher(key)
+}
+
 var shaWithTripleDESCBC = cipherWithBlock{
 	ivSize:   des.BlockSize,
 	keySize:  24,
@@ -22,13 +53,46 @@ var shaWithTripleDESCBC = cipherWithBlock{
 	oid:      oidPBEWithSHAAnd3KeyTripleDESCBC,
 }
 
-var shaWith40BitRC2CBC = cipherWithBlock{
-	ivSize:  rc2.BlockSize,
-	keySize: 5,
+// shaWith2KeyTripleDESCBC is 3DES with a 16 byte key, where the first DES key
+// is reused as the third.
+var shaWith2KeyTripleDESCBC = cipherWithBlock{
+	ivSize:  des.BlockSize,
+	keySize: 16,
 	newBlock: func(key []byte) (cipher.Block, error) {
-		return rc2.New(key, len(key)*8)
+		if len(key) != 16 {
+			return nil, des.KeySizeError(len(key))
+		}
+		return des.NewTripleDESCipher(append(append([]byte{}, key...), key[:8]...))
 	},
-	oid: oidPBEWithSHAAnd40BitRC2CBC,
+	oid: oidPBEWithSHAAnd2KeyTripleDESCBC,
+}
+
+var shaWith128BitRC2CBC = cipherWithBlock{
+	ivSize:   rc2.BlockSize,
+	keySize:  16,
+	newBlock: newRC2,
+	oid:      oidPBEWithSHAAnd128BitRC2CBC,
+}
+
+var shaWith40BitRC2CBC = cipherWithBlock{
+	ivSize:   rc2.BlockSize,
+	keySize:  5,
+	newBlock: newRC2,
+	oid:      oidPBEWithSHAAnd40BitRC2CBC,
+}
+
+var md2WithDESCBC = cipherWithBlock{
+	ivSize:   des.BlockSize,
+	keySize:  8,
+	newBlock: des.NewCipher,
+	oid:      oidPBEWithMD2AndDESCBC,
+}
+
+var md2WithRC2CBC = cipherWithBlock{
+	ivSize:   rc2.BlockSize,
+	keySize:  8,
+	newBlock: newRC2,
+	oid:      oidPBEWithMD2AndRC2CBC,
 }
 
 var md5WithDESCBC = cipherWithBlock{
@@ -38,6 +102,62 @@ var md5WithDESCBC = cipherWithBlock{
 	oid:      oidPBEWithMD5AndDESCBC,
 }
 
+var md5WithRC2CBC = cipherWithBlock{
+	ivSize:   rc2.BlockSize,
+	keySize:  8,
+	newBlock: newRC2,
+	oid:      oidPBEWithMD5AndRC2CBC,
+}
+
+var sha1WithDESCBC = cipherWithBlock{
+	ivSize:   des.BlockSize,
+	keySize:  8,
+	newBlock: des.NewCipher,
+	oid:      oidPBEWithSHA1AndDESCBC,
+}
+
+var sha1WithRC2CBC = cipherWithBlock{
+	ivSize:   rc2.BlockSize,
+	keySize:  8,
+	newBlock: newRC2,
+	oid:      oidPBEWithSHA1AndRC2CBC,
+}
+
+var sha1WithSeedCBC = cipherWithBlock{
+	ivSize:   seed.BlockSize,
+	keySize:  16,
+	newBlock: seed.New,
+	oid:      oidSeedCBCWithSHA1,
+}
+
+// PBEOpts contains options for encrypting a PKCS#8 key with a PKCS#5 v1.5
+// (PBES1) or PKCS#12 password-based encryption scheme rather than PBES2, for
+// systems that do not support PBES2.
+type PBEOpts struct {
+	Scheme asn1.ObjectIdentifier
+	// SaltSize must be 8 for the PKCS#5 v1.5 schemes, and at least 1 for the
+	// PKCS#12 schemes.
+	SaltSize int
+	// IterationCount must be at least 1.
+	IterationCount int
+}
+
+// Password-based encryption schemes for use with PBEOpts.Scheme.
+var (
+	PBEWithSHAAnd128BitRC4        = oidPBEWithSHAAnd128BitRC4
+	PBEWithSHAAnd40BitRC4         = oidPBEWithSHAAnd40BitRC4
+	PBEWithSHAAnd3KeyTripleDESCBC = oidPBEWithSHAAnd3KeyTripleDESCBC
+	PBEWithSHAAnd2KeyTripleDESCBC = oidPBEWithSHAAnd2KeyTripleDESCBC
+	PBEWithSHAAnd128BitRC2CBC     = oidPBEWithSHAAnd128BitRC2CBC
+	PBEWithSHAAnd40BitRC2CBC      = oidPBEWithSHAAnd40BitRC2CBC
+	PBEWithMD2AndDESCBC           = oidPBEWithMD2AndDESCBC
+	PBEWithMD2AndRC2CBC           = oidPBEWithMD2AndRC2CBC
+	PBEWithMD5AndDESCBC           = oidPBEWithMD5AndDESCBC
+	PBEWithMD5AndRC2CBC           = oidPBEWithMD5AndRC2CBC
+	PBEWithSHA1AndDESCBC          = oidPBEWithSHA1AndDESCBC
+	PBEWithSHA1AndRC2CBC          = oidPBEWithSHA1AndRC2CBC
+)
+
 type pbeKDFParameters interface {
 	KDFParameters
 	DeriveIV(password []byte, size int) (key []byte, err error)
@@ -48,11 +168,30 @@ type sha1PbeParams struct {
 	Iterations int
 }
 
+// md2Pkcs5PbeParams, md5Pkcs5PbeParams and sha1Pkcs5PbeParams are the
+// PBEParameter structure from RFC 8018 for the PBES1 schemes using each hash.
+type md2Pkcs5PbeParams struct {
+	Salt       []byte
+	Iterations int
+}
+
 type md5Pkcs5PbeParams struct {
 	Salt       []byte
 	Iterations int
 }
 
+type sha1Pkcs5PbeParams struct {
+	Salt       []byte
+	Iterations int
+}
+
+// npkiSeedPbeParams are the parameters of the seedCBCWithSHA1 scheme used by
+// the Korean NPKI for signPri.key files.
+type npkiSeedPbeParams struct {
+	Salt       []byte
+	Iterations int
+}
+
 func (p sha1PbeParams) pbkdf(password []byte, size int, id byte) (key []byte, err error) {
 	return pkcspbkdf.PKCS12PBKDF(pkcspbkdf.Sha1Sum, pkcspbkdf.Sha1Size, 64, p.Salt, password, p.Iterations, id, size), nil
 }
@@ -65,59 +204,152 @@ func (p sha1PbeParams) DeriveIV(password []byte, size int) (key []byte, err erro
 	return p.pbkdf(password, size, 2)
 }
 
-func (p md5Pkcs5PbeParams) pbkdf(password []byte, size, part int) (key []byte, err error) {
-	key = pkcspbkdf.PKCS5PBKDF1(pkcspbkdf.Md5Sum, p.Salt, password, p.Iterations, size)
+// pbes1 derives the 16 bytes used by PBES1 with PBKDF1, and returns the
+// first half as the key (part 0) or the second half as the IV (part 1).
+func pbes1(hash func([]byte) []byte, salt, password []byte, iterations, part int) (key []byte, err error) {
+	if iterations < 1 {
+		return nil, &MalformedInputError{Field: "PBE iteration count"}
+	}
+	key = pkcspbkdf.PKCS5PBKDF1(hash, salt, password, iterations, 16)
 	return key[part*8 : (part*8)+8], nil
 }
 
+func (p md2Pkcs5PbeParams) DeriveKey(password []byte, size int) (key []byte, err error) {
+	return pbes1(pkcspbkdf.Md2Sum, p.Salt, password, p.Iterations, 0)
+}
+
+func (p md2Pkcs5PbeParams) DeriveIV(password []byte, size int) (key []byte, err error) {
+	return pbes1(pkcspbkdf.Md2Sum, p.Salt, password, p.Iterations, 1)
+}
+
 func (p md5Pkcs5PbeParams) DeriveKey(password []byte, size int) (key []byte, err error) {
-	return p.pbkdf(password, 16, 0)
+	return pbes1(pkcspbkdf.Md5Sum, p.Salt, password, p.Iterations, 0)
 }
 
 func (p md5Pkcs5PbeParams) DeriveIV(password []byte, size int) (key []byte, err error) {
-	return p.pbkdf(password, 16, 1)
+	return pbes1(pkcspbkdf.Md5Sum, p.Salt, password, p.Iterations, 1)
 }
 
-func decryptPBE(privKey encryptedPrivateKeyInfo, password []byte) ([]byte, KDFParameters, error) {
-	var origPassword bool
-	var params pbeKDFParameters
-	var cipherType Cipher
+func (p sha1Pkcs5PbeParams) DeriveKey(password []byte, size int) (key []byte, err error) {
+	return pbes1(pkcspbkdf.Sha1Sum, p.Salt, password, p.Iterations, 0)
+}
+
+func (p sha1Pkcs5PbeParams) DeriveIV(password []byte, size int) (key []byte, err error) {
+	return pbes1(pkcspbkdf.Sha1Sum, p.Salt, password, p.Iterations, 1)
+}
+
+func (p npkiSeedPbeParams) pbkdf(password []byte) ([]byte, error) {
+	if p.Iterations < 1 {
+		return nil, &MalformedInputError{Field: "PBE iteration count"}
+	}
+	return pkcspbkdf.PKCS5PBKDF1(pkcspbkdf.Sha1Sum, p.Salt, password, p.Iterations, pkcspbkdf.Sha1Size), nil
+}
+
+func (p npkiSeedPbeParams) DeriveKey(password []byte, size int) (key []byte, err error) {
+	key, err = p.pbkdf(password)
+	if err != nil {
+		return nil, err
+	}
+	return key[:size], nil
+}
+
+func (p npkiSeedPbeParams) DeriveIV(password []byte, size int) (key []byte, err error) {
+	key, err = p.pbkdf(password)
+	if err != nil {
+		return nil, err
+	}
+	// The IV is the hash of the last four bytes of the derived key
+	iv := pkcspbkdf.Sha1Sum(key[16:])
+	return iv[:size], nil
+}
+
+// pbeScheme returns empty KDF parameters, the cipher and whether the password
+// is used as is rather than as a BMPString for the given PBE scheme.
+func pbeScheme(oid asn1.ObjectIdentifier) (params pbeKDFParameters, cipherType Cipher, origPassword bool, err error) {
 	switch {
-	case privKey.EncryptionAlgorithm.Algorithm.Equal(oidPBEWithSHAAnd3KeyTripleDESCBC):
+	case oid.Equal(oidPBEWithSHAAnd128BitRC4):
+		params = &sha1PbeParams{}
+		cipherType = shaWith128BitRC4
+	case oid.Equal(oidPBEWithSHAAnd40BitRC4):
+		params = &sha1PbeParams{}
+		cipherType = shaWith40BitRC4
+	case oid.Equal(oidPBEWithSHAAnd3KeyTripleDESCBC):
 		params = &sha1PbeParams{}
 		cipherType = shaWithTripleDESCBC
-	case privKey.EncryptionAlgorithm.Algorithm.Equal(oidPBEWithSHAAnd40BitRC2CBC):
+	case oid.Equal(oidPBEWithSHAAnd2KeyTripleDESCBC):
+		params = &sha1PbeParams{}
+		cipherType = shaWith2KeyTripleDESCBC
+	case oid.Equal(oidPBEWithSHAAnd128BitRC2CBC):
+		params = &sha1PbeParams{}
+		cipherType = shaWith128BitRC2CBC
+	case oid.Equal(oidPBEWithSHAAnd40BitRC2CBC):
 		params = &sha1PbeParams{}
 		cipherType = shaWith40BitRC2CBC
-	case privKey.EncryptionAlgorithm.Algorithm.Equal(oidPBEWithMD5AndDESCBC):
+	case oid.Equal(oidPBEWithMD2AndDESCBC):
+		params = &md2Pkcs5PbeParams{}
+		cipherType = md2WithDESCBC
+		origPassword = true
+	case oid.Equal(oidPBEWithMD2AndRC2CBC):
+		params = &md2Pkcs5PbeParams{}
+		cipherType = md2WithRC2CBC
+		origPassword = true
+	case oid.Equal(oidPBEWithMD5AndDESCBC):
 		params = &md5Pkcs5PbeParams{}
 		cipherType = md5WithDESCBC
 		origPassword = true
+	case oid.Equal(oidPBEWithMD5AndRC2CBC):
+		params = &md5Pkcs5PbeParams{}
+		cipherType = md5WithRC2CBC
+		origPassword = true
+	case oid.Equal(oidPBEWithSHA1AndDESCBC):
+		params = &sha1Pkcs5PbeParams{}
+		cipherType = sha1WithDESCBC
+		origPassword = true
+	case oid.Equal(oidPBEWithSHA1AndRC2CBC):
+		params = &sha1Pkcs5PbeParams{}
+		cipherType = sha1WithRC2CBC
+		origPassword = true
+	case oid.Equal(oidSeedCBCWithSHA1):
+		params = &npkiSeedPbeParams{}
+		cipherType = sha1WithSeedCBC
+		origPassword = true
 	default:
-		return nil, nil, errors.New("pkcs8: unsupported algorithm: " + privKey.EncryptionAlgorithm.Algorithm.String())
+		return nil, nil, false, &UnsupportedAlgorithmError{Kind: "encryption scheme", OID: oid}
+	}
+
+	return params, cipherType, origPassword, nil
+}
+
+func decryptPBE(privKey encryptedPrivateKeyInfo, password []byte) ([]byte, KDFParameters, error) {
+	params, cipherType, origPassword, err := pbeScheme(privKey.EncryptionAlgorithm.Algorithm)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	if !origPassword {
-		var err error
 		password, err = bmpStringZeroTerminated(string(password))
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, &MalformedInputError{Field: "password", Err: err}
 		}
 	}
 
-	err := unmarshal(privKey.EncryptionAlgorithm.Parameters.FullBytes, params)
+	err = unmarshal(privKey.EncryptionAlgorithm.Parameters.FullBytes, params)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, &MalformedInputError{Field: "PBE parameters", Err: err}
 	}
 
 	symKey, err := params.DeriveKey(password, cipherType.KeySize())
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, kdfError(err)
 	}
 
-	iv, err := params.DeriveIV(password, cipherType.IVSize())
-	if err != nil {
-		return nil, nil, err
+	// Stream ciphers such as RC4 take no IV
+	var iv []byte
+	if cipherType.IVSize() > 0 {
+		iv, err = params.DeriveIV(password, cipherType.IVSize())
+		if err != nil {
+			return nil, nil, kdfError(err)
+		}
 	}
 
 	encryptedKey := privKey.EncryptedData
@@ -128,3 +360,73 @@ func decryptPBE(privKey encryptedPrivateKeyInfo, password []byte) ([]byte, KDFPa
 
 	return decryptedKey, params, nil
 }
+
+func encryptPBE(pkey, password []byte, opts *PBEOpts) ([]byte, error) {
+	params, cipherType, origPassword, err := pbeScheme(opts.Scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.IterationCount < 1 {
+		return nil, errors.New("pkcs8: PBE iteration count must be at least 1")
+	}
+	switch params.(type) {
+	case *md2Pkcs5PbeParams, *md5Pkcs5PbeParams, *sha1Pkcs5PbeParams:
+		if opts.SaltSize != 8 {
+			return nil, errors.New("pkcs8: PBES1 salt size must be 8")
+		}
+	default:
+		if opts.SaltSize < 1 {
+			return nil, errors.New("pkcs8: PBE salt size must be at least 1")
+		}
+	}
+
+	if !origPassword {
+		password, err = bmpStringZeroTerminated(string(password))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	salt := make([]byte, opts.SaltSize)
+	_, err = rand.Read(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	// All PBE schemes share the PBEParameter encoding of sha1PbeParams
+	marshalledParams, err := asn1.Marshal(sha1PbeParams{Salt: salt, Iterations: opts.IterationCount})
+	if err != nil {
+		return nil, err
+	}
+	err = unmarshal(marshalledParams, params)
+	if err != nil {
+		return nil, err
+	}
+
+	symKey, err := params.DeriveKey(password, cipherType.KeySize())
+	if err != nil {
+		return nil, err
+	}
+
+	var iv []byte
+	if cipherType.IVSize() > 0 {
+		iv, err = params.DeriveIV(password, cipherType.IVSize())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	encryptedKey, err := cipherType.Encrypt(symKey, iv, pkey)
+	if err != nil {
+		return nil, err
+	}
+
+	return asn1.Marshal(encryptedPrivateKeyInfo{
+		EncryptionAlgorithm: pkix.AlgorithmIdentifier{
+			Algorithm:  opts.Scheme,
+			Parameters: asn1.RawValue{FullBytes: marshalledParams},
+		},
+		EncryptedData: encryptedKey,
+	})
+}
@@ -0,0 +1,101 @@
+package pkcs8
+
+import (
+	"crypto/cipher"
+	"encoding/asn1"
+	"errors"
+
+	"github.com/nvx/pkcs8/internal/rc2"
+)
+
+var (
+	oidRC2CBC = asn1.ObjectIdentifier{1, 2, 840, 113549, 3, 2}
+)
+
+func init() {
+	RegisterCipher(oidRC2CBC, func() Cipher {
+		return rc2CBC
+	})
+}
+
+// rc2CBC is the RC2 cipher in CBC mode, with the effective key length taken
+// from the RC2CBCParameter structure.
+var rc2CBC = rc2CBCCipher{cipherWithBlock{
+	ivSize:   rc2.BlockSize,
+	keySize:  16,
+	newBlock: newRC2,
+	oid:      oidRC2CBC,
+}}
+
+func newRC2(key []byte) (cipher.Block, error) {
+	return rc2.New(key, len(key)*8)
+}
+
+// rc2CBCCipher is RC2 in CBC mode whose parameters are the RC2CBCParameter
+// structure from RFC 8018. The key size is the effective key length.
+type rc2CBCCipher struct {
+	cipherWithBlock
+}
+
+// rc2CBCParameter is the RC2-CBC-Parameter structure from RFC 8018.
+type rc2CBCParameter struct {
+	RC2ParameterVersion int `asn1:"optional"`
+	IV                  []byte
+}
+
+// rc2CBCParameterIV is the RC2-CBC-Parameter structure without the optional
+// rc2ParameterVersion, which RFC 2268 defines as an effective key length of
+// 32 bits.
+type rc2CBCParameterIV struct {
+	IV []byte
+}
+
+// rc2Versions maps the effective key lengths in bits supported by OpenSSL to
+// their rc2ParameterVersion encoding from RFC 2268. Lengths of 256 bits or
+// more are encoded as themselves.
+var rc2Versions = map[int]int{
+	40:  160,
+	64:  120,
+	128: 58,
+}
+
+func (c rc2CBCCipher) MarshalParameters(iv []byte) ([]byte, error) {
+	bits := c.keySize * 8
+	version, ok := rc2Versions[bits]
+	if !ok {
+		if bits < 256 {
+			return nil, errors.New("pkcs8: unsupported RC2 effective key length")
+		}
+		version = bits
+	}
+	return asn1.Marshal(rc2CBCParameter{RC2ParameterVersion: version, IV: iv})
+}
+
+func (c rc2CBCCipher) UnmarshalParameters(der []byte) (Cipher, []byte, error) {
+	var ivOnly rc2CBCParameterIV
+	if err := unmarshal(der, &ivOnly); err == nil {
+		c.keySize = 4
+		return c, ivOnly.IV, nil
+	}
+	var params rc2CBCParameter
+	if err := unmarshal(der, &params); err != nil {
+		return nil, nil, err
+	}
+	bits := params.RC2ParameterVersion
+	if bits < 256 {
+		bits = 0
+		for b, v := range rc2Versions {
+			if v == params.RC2ParameterVersion {
+				bits = b
+			}
+		}
+		if bits == 0 {
+			return nil, nil, &UnsupportedAlgorithmError{Kind: "cipher", OID: c.OID()}
+		}
+	}
+	if bits%8 != 0 || bits > 1024 {
+		return nil, nil, &UnsupportedAlgorithmError{Kind: "cipher", OID: c.OID()}
+	}
+	c.keySize = bits / 8
+	return c, params.IV, nil
+}
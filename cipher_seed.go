@@ -0,0 +1,25 @@
+package pkcs8
+
+import (
+	"encoding/asn1"
+
+	"github.com/nvx/pkcs8/internal/seed"
+)
+
+var (
+	oidSeedCBC = asn1.ObjectIdentifier{1, 2, 410, 200004, 1, 4}
+)
+
+func init() {
+	RegisterCipher(oidSeedCBC, func() Cipher {
+		return SeedCBC
+	})
+}
+
+// SeedCBC is the 128-bit key SEED cipher in CBC mode.
+var SeedCBC = cipherWithBlock{
+	ivSize:   seed.BlockSize,
+	keySize:  16,
+	newBlock: seed.New,
+	oid:      oidSeedCBC,
+}
@@ -0,0 +1,25 @@
+package pkcs8
+
+import (
+	"encoding/asn1"
+
+	"github.com/nvx/pkcs8/internal/sm4"
+)
+
+var (
+	oidSM4CBC = asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 104, 2}
+)
+
+func init() {
+	RegisterCipher(oidSM4CBC, func() Cipher {
+		return SM4CBC
+	})
+}
+
+// SM4CBC is the 128-bit key SM4 cipher in CBC mode.
+var SM4CBC = cipherWithBlock{
+	ivSize:   sm4.BlockSize,
+	keySize:  16,
+	newBlock: sm4.New,
+	oid:      oidSM4CBC,
+}
@@ -0,0 +1,55 @@
+package pkcs8
+
+import (
+	"encoding/asn1"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrIncorrectPassword is returned when an encrypted key cannot be
+	// decrypted with the given password. For ciphers without integrity
+	// protection, a corrupted key also results in this error.
+	ErrIncorrectPassword = errors.New("pkcs8: incorrect password")
+	// ErrPasswordRequired is returned when an encrypted key is parsed without
+	// a password.
+	ErrPasswordRequired = errors.New("pkcs8: password required")
+	// ErrNotEncrypted is returned when an unencrypted key is given where an
+	// encrypted key is required.
+	ErrNotEncrypted = errors.New("pkcs8: key is not encrypted")
+)
+
+// UnsupportedAlgorithmError is returned when a key uses an encryption scheme,
+// cipher, KDF, PRF, key algorithm or GOST parameter set that is not supported
+// or registered.
+type UnsupportedAlgorithmError struct {
+	// Kind is "encryption scheme", "cipher", "KDF", "PRF", "key algorithm" or
+	// "GOST parameter set".
+	Kind string
+	OID  asn1.ObjectIdentifier
+}
+
+func (e *UnsupportedAlgorithmError) Error() string {
+	return fmt.Sprintf("pkcs8: unsupported %s (OID: %s)", e.Kind, e.OID)
+}
+
+// MalformedInputError is returned when part of a key cannot be decoded or has
+// invalid values.
+type MalformedInputError struct {
+	// Field names the part of the key, for example "PBES2 parameters".
+	Field string
+	// Err is the underlying error, if any.
+	Err error
+}
+
+func (e *MalformedInputError) Error() string {
+	if e.Err != nil {
+		return "pkcs8: invalid " + e.Field + ": " + e.Err.Error()
+	}
+	return "pkcs8: invalid " + e.Field
+}
+
+// Unwrap returns the underlying error.
+func (e *MalformedInputError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,329 @@
+package pkcs8
+
+import "encoding/asn1"
+
+// KeyInfo describes how a PKCS#8 private key is protected, as returned by
+// Inspect.
+type KeyInfo struct {
+	// Encrypted reports whether the key is an EncryptedPrivateKeyInfo.
+	Encrypted bool
+	// Algorithm is the private key algorithm OID of an unencrypted key.
+	Algorithm asn1.ObjectIdentifier
+
+	// Scheme is the encryption algorithm OID, either PBES2 or a PBES1 or
+	// PKCS#12 scheme. SchemeName is "PBES2", "PBES1", "PKCS#12" or "NPKI".
+	Scheme     asn1.ObjectIdentifier
+	SchemeName string
+
+	// Cipher is the PBES2 encryption scheme OID, or the same as Scheme for
+	// the other schemes. CipherName is empty for unknown ciphers.
+	Cipher     asn1.ObjectIdentifier
+	CipherName string
+	KeySize    int
+	// IVSize is the size of the IV or nonce. It is derived from the password
+	// rather than stored for PBES1 and PKCS#12 schemes.
+	IVSize         int
+	CiphertextSize int
+
+	// KDF is the PBES2 key derivation function OID, and is nil for the other
+	// schemes. KDFName is empty for unknown KDFs.
+	KDF     asn1.ObjectIdentifier
+	KDFName string
+	// PRF is the PBKDF2 pseudorandom function OID. PRFName is empty for
+	// unknown PRFs.
+	PRF      asn1.ObjectIdentifier
+	PRFName  string
+	Salt     []byte
+	SaltSize int
+	// IterationCount is the PBKDF2, PBES1 or PKCS#12 iteration count, the
+	// number of Argon2 passes or the number of bcrypt_pbkdf rounds.
+	IterationCount int
+	// CostParameter, BlockSize and ParallelizationParameter are the scrypt
+	// N, r and p.
+	CostParameter            int
+	BlockSize                int
+	ParallelizationParameter int
+	// Memory (in kibibytes) and Threads are the Argon2 cost parameters.
+	Memory  int
+	Threads int
+	// KeyLength is the key length encoded in the KDF parameters, if any.
+	KeyLength int
+
+	// cipher and kdfOpts are the decoded cipher and the options recreating
+	// the KDF parameters of a PBES2 key, if supported.
+	cipher  Cipher
+	kdfOpts KDFOpts
+}
+
+// Opts returns options that encrypt a key with the same scheme, cipher and
+// KDF parameters as the inspected key, so that it can be re-encrypted with the
+// same protection. A new salt and IV are generated when the options are used.
+func (i *KeyInfo) Opts() (*Opts, error) {
+	if !i.Encrypted {
+		return nil, ErrNotEncrypted
+	}
+	if !i.Scheme.Equal(oidPBES2) {
+		return &Opts{
+			PBE: &PBEOpts{
+				Scheme:         i.Scheme,
+				SaltSize:       i.SaltSize,
+				IterationCount: i.IterationCount,
+			},
+		}, nil
+	}
+	if i.cipher == nil {
+		return nil, &UnsupportedAlgorithmError{Kind: "cipher", OID: i.Cipher}
+	}
+	if i.kdfOpts == nil {
+		return nil, &UnsupportedAlgorithmError{Kind: "KDF", OID: i.KDF}
+	}
+	return &Opts{
+		Cipher:  i.cipher,
+		KDFOpts: i.kdfOpts,
+	}, nil
+}
+
+var algorithmNames = map[string]string{
+	oidDESCBC.String():             "DES-CBC",
+	oidDESEDE3CBC.String():         "DES-EDE3-CBC",
+	oidRC2CBC.String():             "RC2-CBC",
+	oidCAST5CBC.String():           "CAST5-CBC",
+	oidBlowfishCBC.String():        "BF-CBC",
+	oidAES128CBC.String():          "AES-128-CBC",
+	oidAES192CBC.String():          "AES-192-CBC",
+	oidAES256CBC.String():          "AES-256-CBC",
+	oidAES128GCM.String():          "AES-128-GCM",
+	oidAES192GCM.String():          "AES-192-GCM",
+	oidAES256GCM.String():          "AES-256-GCM",
+	oidAES128CCM.String():          "AES-128-CCM",
+	oidAES192CCM.String():          "AES-192-CCM",
+	oidAES256CCM.String():          "AES-256-CCM",
+	oidChaCha20Poly1305.String():   "ChaCha20-Poly1305",
+	oidCamellia128CBC.String():     "Camellia-128-CBC",
+	oidCamellia192CBC.String():     "Camellia-192-CBC",
+	oidCamellia256CBC.String():     "Camellia-256-CBC",
+	oidARIA128CBC.String():         "ARIA-128-CBC",
+	oidARIA192CBC.String():         "ARIA-192-CBC",
+	oidARIA256CBC.String():         "ARIA-256-CBC",
+	oidSeedCBC.String():            "SEED-CBC",
+	oidSM4CBC.String():             "SM4-CBC",
+	oidMagmaCTRACPKM.String():      "Magma-CTR-ACPKM",
+	oidKuznyechikCTRACPKM.String(): "Kuznyechik-CTR-ACPKM",
+
+	oidPKCS5PBKDF2.String(): "PBKDF2",
+	oidScrypt.String():      "scrypt",
+	oidArgon2i.String():     "Argon2i",
+	oidArgon2id.String():    "Argon2id",
+
+	oidHMACWithSHA1.String():        "hmacWithSHA1",
+	oidHMACWithSHA224.String():      "hmacWithSHA224",
+	oidHMACWithSHA256.String():      "hmacWithSHA256",
+	oidHMACWithSHA384.String():      "hmacWithSHA384",
+	oidHMACWithSHA512.String():      "hmacWithSHA512",
+	oidHMACWithSHA512_224.String():  "hmacWithSHA512-224",
+	oidHMACWithSHA512_256.String():  "hmacWithSHA512-256",
+	oidHMACWithSHA3_224.String():    "hmacWithSHA3-224",
+	oidHMACWithSHA3_256.String():    "hmacWithSHA3-256",
+	oidHMACWithSHA3_384.String():    "hmacWithSHA3-384",
+	oidHMACWithSHA3_512.String():    "hmacWithSHA3-512",
+	oidHMACWithSM3.String():         "hmacWithSM3",
+	oidHMACWithStreebog256.String(): "hmacWithStreebog256",
+	oidHMACWithStreebog512.String(): "hmacWithStreebog512",
+}
+
+// pbeNames are the scheme, KDF and cipher names of the PBES1 and PKCS#12
+// schemes.
+var pbeNames = map[string][3]string{
+	oidPBEWithSHAAnd128BitRC4.String():        {"PKCS#12", "PKCS12KDF-SHA1", "RC4"},
+	oidPBEWithSHAAnd40BitRC4.String():         {"PKCS#12", "PKCS12KDF-SHA1", "RC4-40"},
+	oidPBEWithSHAAnd3KeyTripleDESCBC.String(): {"PKCS#12", "PKCS12KDF-SHA1", "DES-EDE3-CBC"},
+	oidPBEWithSHAAnd2KeyTripleDESCBC.String(): {"PKCS#12", "PKCS12KDF-SHA1", "DES-EDE-CBC"},
+	oidPBEWithSHAAnd128BitRC2CBC.String():     {"PKCS#12", "PKCS12KDF-SHA1", "RC2-CBC"},
+	oidPBEWithSHAAnd40BitRC2CBC.String():      {"PKCS#12", "PKCS12KDF-SHA1", "RC2-40-CBC"},
+	oidPBEWithMD2AndDESCBC.String():           {"PBES1", "PBKDF1-MD2", "DES-CBC"},
+	oidPBEWithMD2AndRC2CBC.String():           {"PBES1", "PBKDF1-MD2", "RC2-64-CBC"},
+	oidPBEWithMD5AndDESCBC.String():           {"PBES1", "PBKDF1-MD5", "DES-CBC"},
+	oidPBEWithMD5AndRC2CBC.String():           {"PBES1", "PBKDF1-MD5", "RC2-64-CBC"},
+	oidPBEWithSHA1AndDESCBC.String():          {"PBES1", "PBKDF1-SHA1", "DES-CBC"},
+	oidPBEWithSHA1AndRC2CBC.String():          {"PBES1", "PBKDF1-SHA1", "RC2-64-CBC"},
+	oidSeedCBCWithSHA1.String():               {"NPKI", "PBKDF1-SHA1", "SEED-CBC"},
+}
+
+// Inspect describes how a DER-encoded PKCS#8 private key is protected without
+// decrypting it, so no password is needed. For an unencrypted key only the
+// private key algorithm is reported.
+//
+// Ciphers and KDFs that are not registered are reported by OID only.
+func Inspect(der []byte) (*KeyInfo, error) {
+	var privKey encryptedPrivateKeyInfo
+	if err := unmarshal(der, &privKey); err != nil {
+		var info privateKeyInfo
+		if err := unmarshal(der, &info); err != nil {
+			return nil, &MalformedInputError{Field: "PrivateKeyInfo", Err: err}
+		}
+		return &KeyInfo{Algorithm: info.Algo.Algorithm}, nil
+	}
+
+	info := &KeyInfo{
+		Encrypted:      true,
+		Scheme:         privKey.EncryptionAlgorithm.Algorithm,
+		CiphertextSize: len(privKey.EncryptedData),
+	}
+	var err error
+	if privKey.EncryptionAlgorithm.Algorithm.Equal(oidPBES2) {
+		err = inspectPBES2(info, privKey)
+	} else {
+		err = inspectPBE(info, privKey)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func inspectPBES2(info *KeyInfo, privKey encryptedPrivateKeyInfo) error {
+	var params pbes2Params
+	err := unmarshal(privKey.EncryptionAlgorithm.Parameters.FullBytes, &params)
+	if err != nil {
+		return &MalformedInputError{Field: "PBES2 parameters", Err: err}
+	}
+
+	info.SchemeName = "PBES2"
+	info.Cipher = params.EncryptionScheme.Algorithm
+	info.KDF = params.KeyDerivationFunc.Algorithm
+
+	if _, ok := ciphers[info.Cipher.String()]; ok {
+		cipherType, iv, err := parseEncryptionScheme(params.EncryptionScheme)
+		if err != nil {
+			return err
+		}
+		info.cipher = cipherType
+		info.CipherName = algorithmNames[info.Cipher.String()]
+		info.KeySize = cipherType.KeySize()
+		info.IVSize = len(iv)
+	}
+
+	if _, ok := kdfs[info.KDF.String()]; ok {
+		kdfParams, err := parseKeyDerivationFunc(params.KeyDerivationFunc)
+		if err != nil {
+			return err
+		}
+		info.KDFName = algorithmNames[info.KDF.String()]
+		inspectKDFParameters(info, kdfParams)
+	}
+	return nil
+}
+
+func inspectPBE(info *KeyInfo, privKey encryptedPrivateKeyInfo) error {
+	params, cipherType, _, err := pbeScheme(privKey.EncryptionAlgorithm.Algorithm)
+	if err != nil {
+		return err
+	}
+	err = unmarshal(privKey.EncryptionAlgorithm.Parameters.FullBytes, params)
+	if err != nil {
+		return &MalformedInputError{Field: "PBE parameters", Err: err}
+	}
+
+	names := pbeNames[info.Scheme.String()]
+	info.SchemeName = names[0]
+	info.KDFName = names[1]
+	info.Cipher = cipherType.OID()
+	info.CipherName = names[2]
+	info.KeySize = cipherType.KeySize()
+	info.IVSize = cipherType.IVSize()
+	inspectKDFParameters(info, params)
+	return nil
+}
+
+func inspectKDFParameters(info *KeyInfo, params KDFParameters) {
+	switch p := params.(type) {
+	case *pbkdf2Params:
+		info.Salt = p.Salt
+		info.IterationCount = p.IterationCount
+		info.KeyLength = p.KeyLength
+		info.PRF = p.PRF.Algorithm
+		// The PRF defaults to hmacWithSHA1
+		if len(info.PRF) == 0 {
+			info.PRF = oidHMACWithSHA1
+		}
+		info.PRFName = algorithmNames[info.PRF.String()]
+		opts := PBKDF2Opts{
+			SaltSize:         len(p.Salt),
+			IterationCount:   p.IterationCount,
+			PRF:              info.PRF,
+			IncludeKeyLength: p.KeyLength != 0,
+		}
+		// Prefer HMACHash for PRFs registered with a crypto.Hash
+		for h, oid := range prfOIDs {
+			if oid.Equal(info.PRF) {
+				opts.HMACHash = h
+				opts.PRF = nil
+				break
+			}
+		}
+		info.kdfOpts = opts
+	case *scryptParams:
+		info.Salt = p.Salt
+		info.CostParameter = p.CostParameter
+		info.BlockSize = p.BlockSize
+		info.ParallelizationParameter = p.ParallelizationParameter
+		info.KeyLength = p.KeyLength
+		info.kdfOpts = ScryptOpts{
+			SaltSize:                 len(p.Salt),
+			CostParameter:            p.CostParameter,
+			BlockSize:                p.BlockSize,
+			ParallelizationParameter: p.ParallelizationParameter,
+			IncludeKeyLength:         p.KeyLength != 0,
+		}
+	case *argon2idParams:
+		inspectArgon2Parameters(info, *p)
+		info.kdfOpts = Argon2Opts{
+			SaltSize: len(p.Salt),
+			Time:     uint32(info.IterationCount),
+			Memory:   uint32(info.Memory),
+			Threads:  uint8(info.Threads),
+		}
+	case *argon2iParams:
+		inspectArgon2Parameters(info, argon2idParams(*p))
+		info.kdfOpts = Argon2iOpts{
+			SaltSize: len(p.Salt),
+			Time:     uint32(info.IterationCount),
+			Memory:   uint32(info.Memory),
+			Threads:  uint8(info.Threads),
+		}
+	case *bcryptPBKDFParams:
+		info.Salt = p.Salt
+		info.IterationCount = p.Rounds
+		info.kdfOpts = BcryptPBKDFOpts{
+			SaltSize:  len(p.Salt),
+			Rounds:    p.Rounds,
+			Algorithm: info.KDF,
+		}
+	case *sha1PbeParams:
+		info.Salt = p.Salt
+		info.IterationCount = p.Iterations
+	case *md2Pkcs5PbeParams:
+		info.Salt = p.Salt
+		info.IterationCount = p.Iterations
+	case *md5Pkcs5PbeParams:
+		info.Salt = p.Salt
+		info.IterationCount = p.Iterations
+	case *sha1Pkcs5PbeParams:
+		info.Salt = p.Salt
+		info.IterationCount = p.Iterations
+	case *npkiSeedPbeParams:
+		info.Salt = p.Salt
+		info.IterationCount = p.Iterations
+	}
+	info.SaltSize = len(info.Salt)
+}
+
+func inspectArgon2Parameters(info *KeyInfo, p argon2idParams) {
+	info.Salt = p.Salt
+	info.IterationCount = p.Passes
+	info.Threads = p.Parallelism
+	if p.MemoryExponent >= 0 && p.MemoryExponent < 31 {
+		info.Memory = 1 << uint(p.MemoryExponent)
+	}
+	info.KeyLength = p.OutputLength
+}
@@ -0,0 +1,123 @@
+// Package acpkm implements the CTR-ACPKM block cipher mode, which rekeys the
+// cipher after every section of the key stream
+/*
+https://tools.ietf.org/html/rfc8645
+GOST R 34.13-2015
+*/
+package acpkm
+
+import (
+	"crypto/cipher"
+	"errors"
+)
+
+// d is the constant D from section 6.1 of RFC 8645, 0x80 | 0x81 | ... | 0x9f
+var d = func() (d [32]byte) {
+	for i := range d {
+		d[i] = 0x80 + byte(i)
+	}
+	return d
+}()
+
+type ctr struct {
+	newBlock    func(key []byte) (cipher.Block, error)
+	block       cipher.Block
+	key         []byte
+	ctr         []byte
+	out         []byte
+	outUsed     int
+	sectionSize int
+	sectionUsed int
+	err         error
+}
+
+// NewCTR returns a cipher.Stream encrypting in CTR-ACPKM mode. The counter
+// starts at iv followed by zero bytes, iv must be half the block size.
+// The key is replaced after every sectionSize bytes of key stream, which must be a
+// multiple of the block size.
+func NewCTR(newBlock func(key []byte) (cipher.Block, error), key, iv []byte, sectionSize int) (cipher.Stream, error) {
+	block, err := newBlock(key)
+	if err != nil {
+		return nil, err
+	}
+	bs := block.BlockSize()
+	if len(iv) != bs/2 {
+		return nil, errors.New("acpkm: IV length must be half the block size")
+	}
+	if sectionSize <= 0 || sectionSize%bs != 0 {
+		return nil, errors.New("acpkm: section size must be a multiple of the block size")
+	}
+	c := &ctr{
+		newBlock:    newBlock,
+		block:       block,
+		key:         append([]byte(nil), key...),
+		ctr:         make([]byte, bs),
+		out:         make([]byte, bs),
+		outUsed:     bs,
+		sectionSize: sectionSize,
+	}
+	copy(c.ctr, iv)
+	return c, nil
+}
+
+// rekey replaces the key with ACPKM(key), the first len(key) bytes of the
+// encryption of D under the current key
+func (c *ctr) rekey() error {
+	bs := c.block.BlockSize()
+	next := make([]byte, len(d))
+	for i := 0; i < len(d); i += bs {
+		c.block.Encrypt(next[i:], d[i:i+bs])
+	}
+	block, err := c.newBlock(next[:len(c.key)])
+	if err != nil {
+		return err
+	}
+	c.block = block
+	c.key = next[:len(c.key)]
+	return nil
+}
+
+func (c *ctr) refill() {
+	if c.sectionUsed == c.sectionSize {
+		if err := c.rekey(); err != nil {
+			c.err = err
+			return
+		}
+		c.sectionUsed = 0
+	}
+	c.block.Encrypt(c.out, c.ctr)
+	c.outUsed = 0
+	c.sectionUsed += len(c.out)
+
+	// increment the counter as a big-endian integer
+	for i := len(c.ctr) - 1; i >= 0; i-- {
+		c.ctr[i]++
+		if c.ctr[i] != 0 {
+			break
+		}
+	}
+}
+
+func (c *ctr) XORKeyStream(dst, src []byte) {
+	if len(dst) < len(src) {
+		panic("acpkm: output smaller than input")
+	}
+	for len(src) > 0 {
+		if c.outUsed == len(c.out) {
+			c.refill()
+			if c.err != nil {
+				panic(c.err)
+			}
+		}
+		n := len(src)
+		if rem := len(c.out) - c.outUsed; n > rem {
+			n = rem
+		}
+		for i := 0; i < n; i++ {
+			dst[i] = src[i] ^ c.out[c.outUsed+i]
+		}
+		c.outUsed += n
+		dst = dst[n:]
+		src = src[n:]
+	}
+}
@@ -0,0 +1,89 @@
+package acpkm
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"encoding/hex"
+	"testing"
+
+	"github.com/nvx/pkcs8/internal/kuznyechik"
+	"github.com/nvx/pkcs8/internal/magma"
+)
+
+func decodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestCTRACPKM(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		newBlock    func(key []byte) (cipher.Block, error)
+		key, iv     string
+		sectionSize int
+		plaintext   string
+		ciphertext  string
+	}{
+		{
+			// Without a key change CTR-ACPKM is CTR mode, example from appendix A.1.2 of GOST R 34.13-2015
+			name:        "kuznyechik CTR",
+			newBlock:    kuznyechik.New,
+			key:         "8899aabbccddeeff0011223344556677fedcba98765432100123456789abcdef",
+			iv:          "1234567890abcef0",
+			sectionSize: 4096,
+			plaintext:   "1122334455667700ffeeddccbbaa998800112233445566778899aabbcceeff0a112233445566778899aabbcceeff0a002233445566778899aabbcceeff0a0011",
+			ciphertext:  "f195d8bec10ed1dbd57b5fa240bda1b885eee733f6a13e5df33ce4b33c45dee4a5eae88be6356ed3d5e877f13564a3a5cb91fab1f20cbab6d1c6d15820bdba73",
+		},
+		{
+			// Example from appendix A.2.2 of GOST R 34.13-2015
+			name:        "magma CTR",
+			newBlock:    magma.New,
+			key:         "ffeeddccbbaa99887766554433221100f0f1f2f3f4f5f6f7f8f9fafbfcfdfeff",
+			iv:          "12345678",
+			sectionSize: 1024,
+			plaintext:   "92def06b3c130a59db54c704f8189d204a98fb2e67a8024c8912409b17b57e41",
+			ciphertext:  "4e98110c97b7b93c3e250d93d6e85d69136d868807b2dbef568eb680ab52a12d",
+		},
+		{
+			// Example from appendix A.1 of RFC 8645 with a section size of 256 bits
+			name:        "kuznyechik CTR-ACPKM",
+			newBlock:    kuznyechik.New,
+			key:         "8899aabbccddeeff0011223344556677fedcba98765432100123456789abcdef",
+			iv:          "1234567890abcef0",
+			sectionSize: 32,
+			plaintext:   "1122334455667700ffeeddccbbaa998800112233445566778899aabbcceeff0a112233445566778899aabbcceeff0a002233445566778899aabbcceeff0a001133445566778899aabbcceeff0a001122445566778899aabbcceeff0a001122335566778899aabbcceeff0a0011223344",
+			ciphertext:  "f195d8bec10ed1dbd57b5fa240bda1b885eee733f6a13e5df33ce4b33c45dee44bceeb8f646f4c55001706275e85e800587c4df568d094393e4834afd0805046cf30f57686aeece11cfc6c316b8a896edffd07ec813636460c4f3b743423163e6409a9c282fac8d469d221e7fbd6de5d",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			pt := decodeHex(tt.plaintext)
+			want := decodeHex(tt.ciphertext)
+			s, err := NewCTR(tt.newBlock, decodeHex(tt.key), decodeHex(tt.iv), tt.sectionSize)
+			if err != nil {
+				t.Fatalf("NewCTR returned: %s", err)
+			}
+			got := make([]byte, len(pt))
+			s.XORKeyStream(got, pt)
+			if !bytes.Equal(got, want) {
+				t.Errorf("got %x, want %x", got, want)
+			}
+
+			// uneven writes
+			s, _ = NewCTR(tt.newBlock, decodeHex(tt.key), decodeHex(tt.iv), tt.sectionSize)
+			got = make([]byte, len(pt))
+			for i := 0; i < len(pt); i += 5 {
+				end := i + 5
+				if end > len(pt) {
+					end = len(pt)
+				}
+				s.XORKeyStream(got[i:end], pt[i:end])
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("uneven writes: got %x, want %x", got, want)
+			}
+		})
+	}
+}
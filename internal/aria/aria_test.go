@@ -0,0 +1,55 @@
+package aria
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	// Test vectors from appendix A of RFC 5794
+	var tests = []struct {
+		key    string
+		plain  string
+		cipher string
+	}{
+		{
+			"000102030405060708090a0b0c0d0e0f",
+			"00112233445566778899aabbccddeeff",
+			"d718fbd6ab644c739da95f3be6451778",
+		},
+		{
+			"000102030405060708090a0b0c0d0e0f1011121314151617",
+			"00112233445566778899aabbccddeeff",
+			"26449c1805dbe7aa25a468ce263a9e79",
+		},
+		{
+			"000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f",
+			"00112233445566778899aabbccddeeff",
+			"f92bd7c79fb72e2f2b8f80c1972d24fc",
+		},
+	}
+
+	for _, tt := range tests {
+		k, _ := hex.DecodeString(tt.key)
+		p, _ := hex.DecodeString(tt.plain)
+		c, _ := hex.DecodeString(tt.cipher)
+
+		b, err := New(k)
+		if err != nil {
+			t.Fatalf("New(%s) returned: %s", tt.key, err)
+		}
+
+		var dst [BlockSize]byte
+
+		b.Encrypt(dst[:], p)
+		if !bytes.Equal(dst[:], c) {
+			t.Errorf("encrypt failed: got % 2x wanted % 2x\n", dst, c)
+		}
+
+		b.Decrypt(dst[:], c)
+		if !bytes.Equal(dst[:], p) {
+			t.Errorf("decrypt failed: got % 2x wanted % 2x\n", dst, p)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Package bcryptpbkdf implements bcrypt_pbkdf, the password-based key
+// derivation function used by OpenSSH private keys and signify
+/*
+https://github.com/openbsd/src/blob/master/lib/libutil/bcrypt_pbkdf.c
+*/
+package bcryptpbkdf
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+
+	"golang.org/x/crypto/blowfish"
+)
+
+// hashSize is the size of the output of the bcrypt hash in bytes
+const hashSize = 32
+
+// magic is the plaintext encrypted by the bcrypt hash
+var magic = []byte("OxychromaticBlowfishSwatDynamite")
+
+// Key derives a key of keyLen bytes from the password and salt using the
+// given number of rounds.
+func Key(password, salt []byte, rounds, keyLen int) ([]byte, error) {
+	if rounds < 1 {
+		return nil, errors.New("bcryptpbkdf: number of rounds is too small")
+	}
+	if len(password) == 0 {
+		return nil, errors.New("bcryptpbkdf: empty password")
+	}
+	if len(salt) == 0 || len(salt) > 1<<20 {
+		return nil, errors.New("bcryptpbkdf: bad salt length")
+	}
+	if keyLen < 1 || keyLen > 1024 {
+		return nil, errors.New("bcryptpbkdf: bad key length")
+	}
+
+	numBlocks := (keyLen + hashSize - 1) / hashSize
+	key := make([]byte, numBlocks*hashSize)
+
+	h := sha512.New()
+	h.Write(password)
+	shaPass := h.Sum(nil)
+
+	var count [4]byte
+	shaSalt := make([]byte, 0, sha512.Size)
+	tmp := make([]byte, hashSize)
+	out := make([]byte, hashSize)
+	for block := 1; block <= numBlocks; block++ {
+		// The first round hashes the salt and the block counter
+		h.Reset()
+		h.Write(salt)
+		binary.BigEndian.PutUint32(count[:], uint32(block))
+		h.Write(count[:])
+		if err := bcryptHash(tmp, shaPass, h.Sum(shaSalt)); err != nil {
+			return nil, err
+		}
+		copy(out, tmp)
+
+		for i := 2; i <= rounds; i++ {
+			h.Reset()
+			h.Write(tmp)
+			if err := bcryptHash(tmp, shaPass, h.Sum(shaSalt)); err != nil {
+				return nil, err
+			}
+			for j := range out {
+				out[j] ^= tmp[j]
+			}
+		}
+
+		// The output of each block is interleaved into the key
+		for i, v := range out {
+			key[i*numBlocks+(block-1)] = v
+		}
+	}
+	return key[:keyLen], nil
+}
+
+// bcryptHash is the bcrypt hash of the SHA-512 hashed password and salt
+func bcryptHash(out, shaPass, shaSalt []byte) error {
+	c, err := blowfish.NewSaltedCipher(shaPass, shaSalt)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < 64; i++ {
+		blowfish.ExpandKey(shaSalt, c)
+		blowfish.ExpandKey(shaPass, c)
+	}
+
+	copy(out, magic)
+	for i := 0; i < hashSize; i += blowfish.BlockSize {
+		for j := 0; j < 64; j++ {
+			c.Encrypt(out[i:i+blowfish.BlockSize], out[i:i+blowfish.BlockSize])
+		}
+	}
+
+	// The result is read as little-endian 32-bit words
+	for i := 0; i < hashSize; i += 4 {
+		out[i], out[i+1], out[i+2], out[i+3] = out[i+3], out[i+2], out[i+1], out[i]
+	}
+	return nil
+}
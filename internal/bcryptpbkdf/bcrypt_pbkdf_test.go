@@ -0,0 +1,48 @@
+package bcryptpbkdf
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+	// Outputs of the OpenBSD reference implementation
+	var tests = []struct {
+		password string
+		salt     string
+		rounds   int
+		out      string
+	}{
+		{
+			"password",
+			"salt",
+			12,
+			"1ae42c05d487bc02f64921a4ebe4ea93bcacfe135fda99974c06b7b01fae149a",
+		},
+		{
+			"passwordy\x00PASSWORD\x00",
+			"salty\x00SALT\x00",
+			3,
+			"7f310bd3e78c3280c59ce4595211a2928e8d4ec744c1ed2efc9f764e3388e0ad",
+		},
+		{
+			"секретное слово",
+			"посолить немножко",
+			8,
+			"8df43fc6fe131fc47f0c9e39224bd94c70b6fcc8ee8135faddf61156e6cb2733" +
+				"ea765f315a3e1e4afc35bf8687d189254c1e05a6fe80c0617f9183d67260d6a1" +
+				"15c6c94e3603e2303fbb43a76a64523ffda686b1d4518543",
+		},
+	}
+
+	for _, tt := range tests {
+		out, _ := hex.DecodeString(tt.out)
+		key, err := Key([]byte(tt.password), []byte(tt.salt), tt.rounds, len(out))
+		if err != nil {
+			t.Fatalf("Key(%q) returned: %s", tt.password, err)
+		}
+		if got := hex.EncodeToString(key); got != tt.out {
+			t.Errorf("Key(%q) = %s, wanted %s", tt.password, got, tt.out)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+// Package camellia implements the Camellia block cipher
+/*
+https://www.ietf.org/rfc/rfc3713.txt
+*/
+package camellia
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"math/bits"
+	"strconv"
+)
+
+// BlockSize is the camellia block size in bytes
+const BlockSize = 16
+
+type camelliaCipher struct {
+	kw [4]uint64
+	k  [24]uint64
+	ke [6]uint64
+	// rounds is 18 for 128-bit keys and 24 for 192 and 256-bit keys
+	rounds int
+}
+
+// KeySizeError is returned when the key is not 16, 24 or 32 bytes long
+type KeySizeError int
+
+func (k KeySizeError) Error() string {
+	return "camellia: invalid key size " + strconv.Itoa(int(k))
+}
+
+// New returns a new camellia cipher with the given 128, 192 or 256-bit key
+func New(key []byte) (cipher.Block, error) {
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return nil, KeySizeError(len(key))
+	}
+	c := &camelliaCipher{}
+	c.expandKey(key)
+	return c, nil
+}
+
+func (*camelliaCipher) BlockSize() int { return BlockSize }
+
+var sigma = [6]uint64{
+	0xa09e667f3bcc908b,
+	0xb67ae8584caa73b2,
+	0xc6ef372fe94f82be,
+	0x54ff53a5f1d36f1c,
+	0x10e527fade682d1d,
+	0xb05688c2b3e6c1fd,
+}
+
+var sbox1 = [256]byte{
+	112, 130, 44, 236, 179, 39, 192, 229, 228, 133, 87, 53, 234, 12, 174, 65,
+	35, 239, 107, 147, 69, 25, 165, 33, 237, 14, 79, 78, 29, 101, 146, 189,
+	134, 184, 175, 143, 124, 235, 31, 206, 62, 48, 220, 95, 94, 197, 11, 26,
+	166, 225, 57, 202, 213, 71, 93, 61, 217, 1, 90, 214, 81, 86, 108, 77,
+	139, 13, 154, 102, 251, 204, 176, 45, 116, 18, 43, 32, 240, 177, 132, 153,
+	223, 76, 203, 194, 52, 126, 118, 5, 109, 183, 169, 49, 209, 23, 4, 215,
+	20, 88, 58, 97, 222, 27, 17, 28, 50, 15, 156, 22, 83, 24, 242, 34,
+	254, 68, 207, 178, 195, 181, 122, 145, 36, 8, 232, 168, 96, 252, 105, 80,
+	170, 208, 160, 125, 161, 137, 98, 151, 84, 91, 30, 149, 224, 255, 100, 210,
+	16, 196, 0, 72, 163, 247, 117, 219, 138, 3, 230, 218, 9, 63, 221, 148,
+	135, 92, 131, 2, 205, 74, 144, 51, 115, 103, 246, 243, 157, 127, 191, 226,
+	82, 155, 216, 38, 200, 55, 198, 59, 129, 150, 111, 75, 19, 190, 99, 46,
+	233, 121, 167, 140, 159, 110, 188, 142, 41, 245, 249, 182, 47, 253, 180, 89,
+	120, 152, 6, 106, 231, 70, 113, 186, 212, 37, 171, 66, 136, 162, 141, 250,
+	114, 7, 185, 85, 248, 238, 172, 10, 54, 73, 42, 104, 60, 56, 241, 164,
+	64, 40, 211, 123, 187, 201, 67, 193, 21, 227, 173, 244, 119, 199, 128, 158,
+}
+
+func sbox2(x byte) byte { return bits.RotateLeft8(sbox1[x], 1) }
+func sbox3(x byte) byte { return bits.RotateLeft8(sbox1[x], 7) }
+func sbox4(x byte) byte { return sbox1[bits.RotateLeft8(x, 1)] }
+
+func f(in, ke uint64) uint64 {
+	x := in ^ ke
+	t1 := sbox1[byte(x>>56)]
+	t2 := sbox2(byte(x >> 48))
+	t3 := sbox3(byte(x >> 40))
+	t4 := sbox4(byte(x >> 32))
+	t5 := sbox2(byte(x >> 24))
+	t6 := sbox3(byte(x >> 16))
+	t7 := sbox4(byte(x >> 8))
+	t8 := sbox1[byte(x)]
+	y1 := t1 ^ t3 ^ t4 ^ t6 ^ t7 ^ t8
+	y2 := t1 ^ t2 ^ t4 ^ t5 ^ t7 ^ t8
+	y3 := t1 ^ t2 ^ t3 ^ t5 ^ t6 ^ t8
+	y4 := t2 ^ t3 ^ t4 ^ t5 ^ t6 ^ t7
+	y5 := t1 ^ t2 ^ t6 ^ t7 ^ t8
+	y6 := t2 ^ t3 ^ t5 ^ t7 ^ t8
+	y7 := t3 ^ t4 ^ t5 ^ t6 ^ t8
+	y8 := t1 ^ t4 ^ t5 ^ t6 ^ t7
+	return uint64(y1)<<56 | uint64(y2)<<48 | uint64(y3)<<40 | uint64(y4)<<32 |
+		uint64(y5)<<24 | uint64(y6)<<16 | uint64(y7)<<8 | uint64(y8)
+}
+
+func fl(in, ke uint64) uint64 {
+	x1, x2 := uint32(in>>32), uint32(in)
+	k1, k2 := uint32(ke>>32), uint32(ke)
+	x2 ^= bits.RotateLeft32(x1&k1, 1)
+	x1 ^= x2 | k2
+	return uint64(x1)<<32 | uint64(x2)
+}
+
+func flinv(in, ke uint64) uint64 {
+	y1, y2 := uint32(in>>32), uint32(in)
+	k1, k2 := uint32(ke>>32), uint32(ke)
+	y1 ^= y2 | k2
+	y2 ^= bits.RotateLeft32(y1&k1, 1)
+	return uint64(y1)<<32 | uint64(y2)
+}
+
+// rotl128 rotates the 128-bit value hi||lo left by n bits, 0 <= n < 128
+func rotl128(hi, lo uint64, n uint) (uint64, uint64) {
+	if n >= 64 {
+		hi, lo = lo, hi
+		n -= 64
+	}
+	if n == 0 {
+		return hi, lo
+	}
+	return hi<<n | lo>>(64-n), lo<<n | hi>>(64-n)
+}
+
+func (c *camelliaCipher) expandKey(key []byte) {
+	var klh, kll, krh, krl uint64
+	klh = binary.BigEndian.Uint64(key[0:8])
+	kll = binary.BigEndian.Uint64(key[8:16])
+	switch len(key) {
+	case 24:
+		krh = binary.BigEndian.Uint64(key[16:24])
+		krl = ^krh
+	case 32:
+		krh = binary.BigEndian.Uint64(key[16:24])
+		krl = binary.BigEndian.Uint64(key[24:32])
+	}
+
+	d1, d2 := klh^krh, kll^krl
+	d2 ^= f(d1, sigma[0])
+	d1 ^= f(d2, sigma[1])
+	d1 ^= klh
+	d2 ^= kll
+	d2 ^= f(d1, sigma[2])
+	d1 ^= f(d2, sigma[3])
+	kah, kal := d1, d2
+
+	if len(key) == 16 {
+		c.rounds = 18
+		c.kw[0], c.kw[1] = klh, kll
+		c.k[0], c.k[1] = kah, kal
+		c.k[2], c.k[3] = rotl128(klh, kll, 15)
+		c.k[4], c.k[5] = rotl128(kah, kal, 15)
+		c.ke[0], c.ke[1] = rotl128(kah, kal, 30)
+		c.k[6], c.k[7] = rotl128(klh, kll, 45)
+		c.k[8], _ = rotl128(kah, kal, 45)
+		_, c.k[9] = rotl128(klh, kll, 60)
+		c.k[10], c.k[11] = rotl128(kah, kal, 60)
+		c.ke[2], c.ke[3] = rotl128(klh, kll, 77)
+		c.k[12], c.k[13] = rotl128(klh, kll, 94)
+		c.k[14], c.k[15] = rotl128(kah, kal, 94)
+		c.k[16], c.k[17] = rotl128(klh, kll, 111)
+		c.kw[2], c.kw[3] = rotl128(kah, kal, 111)
+		return
+	}
+
+	d1, d2 = kah^krh, kal^krl
+	d2 ^= f(d1, sigma[4])
+	d1 ^= f(d2, sigma[5])
+	kbh, kbl := d1, d2
+
+	c.rounds = 24
+	c.kw[0], c.kw[1] = klh, kll
+	c.k[0], c.k[1] = kbh, kbl
+	c.k[2], c.k[3] = rotl128(krh, krl, 15)
+	c.k[4], c.k[5] = rotl128(kah, kal, 15)
+	c.ke[0], c.ke[1] = rotl128(krh, krl, 30)
+	c.k[6], c.k[7] = rotl128(kbh, kbl, 30)
+	c.k[8], c.k[9] = rotl128(klh, kll, 45)
+	c.k[10], c.k[11] = rotl128(kah, kal, 45)
+	c.ke[2], c.ke[3] = rotl128(klh, kll, 60)
+	c.k[12], c.k[13] = rotl128(krh, krl, 60)
+	c.k[14], c.k[15] = rotl128(kbh, kbl, 60)
+	c.k[16], c.k[17] = rotl128(klh, kll, 77)
+	c.ke[4], c.ke[5] = rotl128(kah, kal, 77)
+	c.k[18], c.k[19] = rotl128(krh, krl, 94)
+	c.k[20], c.k[21] = rotl128(kah, kal, 94)
+	c.k[22], c.k[23] = rotl128(klh, kll, 111)
+	c.kw[2], c.kw[3] = rotl128(kbh, kbl, 111)
+}
+
+func (c *camelliaCipher) Encrypt(dst, src []byte) {
+	d1 := binary.BigEndian.Uint64(src[0:8]) ^ c.kw[0]
+	d2 := binary.BigEndian.Uint64(src[8:16]) ^ c.kw[1]
+
+	for i := 0; i < c.rounds; i += 2 {
+		if i > 0 && i%6 == 0 {
+			d1 = fl(d1, c.ke[i/3-2])
+			d2 = flinv(d2, c.ke[i/3-1])
+		}
+		d2 ^= f(d1, c.k[i])
+		d1 ^= f(d2, c.k[i+1])
+	}
+
+	d2 ^= c.kw[2]
+	d1 ^= c.kw[3]
+	binary.BigEndian.PutUint64(dst[0:8], d2)
+	binary.BigEndian.PutUint64(dst[8:16], d1)
+}
+
+func (c *camelliaCipher) Decrypt(dst, src []byte) {
+	d1 := binary.BigEndian.Uint64(src[0:8]) ^ c.kw[2]
+	d2 := binary.BigEndian.Uint64(src[8:16]) ^ c.kw[3]
+
+	for i := c.rounds - 1; i > 0; i -= 2 {
+		if i < c.rounds-1 && (i+1)%6 == 0 {
+			d1 = fl(d1, c.ke[(i+1)/3-1])
+			d2 = flinv(d2, c.ke[(i+1)/3-2])
+		}
+		d2 ^= f(d1, c.k[i])
+		d1 ^= f(d2, c.k[i-1])
+	}
+
+	d2 ^= c.kw[0]
+	d1 ^= c.kw[1]
+	binary.BigEndian.PutUint64(dst[0:8], d2)
+	binary.BigEndian.PutUint64(dst[8:16], d1)
+}
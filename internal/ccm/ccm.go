@@ -0,0 +1,181 @@
+// Package ccm implements the CCM (Counter with CBC-MAC) block cipher mode
+/*
+https://www.ietf.org/rfc/rfc3610.txt
+https://nvlpubs.nist.gov/nistpubs/Legacy/SP/nistspecialpublication800-38c.pdf
+*/
+package ccm
+
+import (
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+)
+
+type ccm struct {
+	block     cipher.Block
+	nonceSize int
+	tagSize   int
+}
+
+// New returns the given 128-bit block cipher wrapped in CCM mode with the
+// given nonce size (7 to 13 bytes) and tag size (4 to 16 bytes, even).
+func New(block cipher.Block, nonceSize, tagSize int) (cipher.AEAD, error) {
+	if block.BlockSize() != 16 {
+		return nil, errors.New("ccm: requires 128-bit block cipher")
+	}
+	if nonceSize < 7 || nonceSize > 13 {
+		return nil, errors.New("ccm: invalid nonce size")
+	}
+	if tagSize < 4 || tagSize > 16 || tagSize%2 != 0 {
+		return nil, errors.New("ccm: invalid tag size")
+	}
+	return &ccm{
+		block:     block,
+		nonceSize: nonceSize,
+		tagSize:   tagSize,
+	}, nil
+}
+
+func (c *ccm) NonceSize() int { return c.nonceSize }
+
+func (c *ccm) Overhead() int { return c.tagSize }
+
+// maxLength returns the maximum payload length that fits in the length field.
+func (c *ccm) maxLength() uint64 {
+	q := uint(15 - c.nonceSize)
+	if q >= 8 {
+		return ^uint64(0)
+	}
+	return 1<<(8*q) - 1
+}
+
+func (c *ccm) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	if len(nonce) != c.nonceSize {
+		panic("ccm: incorrect nonce length given to CCM")
+	}
+	if uint64(len(plaintext)) > c.maxLength() {
+		panic("ccm: message too large for CCM")
+	}
+
+	ret, out := sliceForAppend(dst, len(plaintext)+c.tagSize)
+	tag := c.mac(nonce, plaintext, additionalData)
+	c.ctr(nonce, out, plaintext, tag)
+	copy(out[len(plaintext):], tag[:c.tagSize])
+	return ret
+}
+
+func (c *ccm) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(nonce) != c.nonceSize {
+		panic("ccm: incorrect nonce length given to CCM")
+	}
+	if len(ciphertext) < c.tagSize || uint64(len(ciphertext)-c.tagSize) > c.maxLength() {
+		return nil, errors.New("ccm: message authentication failed")
+	}
+
+	tag := make([]byte, 16)
+	copy(tag, ciphertext[len(ciphertext)-c.tagSize:])
+	ciphertext = ciphertext[:len(ciphertext)-c.tagSize]
+
+	ret, out := sliceForAppend(dst, len(ciphertext))
+	c.ctr(nonce, out, ciphertext, tag)
+	expectedTag := c.mac(nonce, out, additionalData)
+	if subtle.ConstantTimeCompare(expectedTag[:c.tagSize], tag[:c.tagSize]) != 1 {
+		for i := range out {
+			out[i] = 0
+		}
+		return nil, errors.New("ccm: message authentication failed")
+	}
+	return ret, nil
+}
+
+// mac computes the CBC-MAC over the formatted nonce, additional data and
+// payload, as described in section 2.2 of RFC 3610.
+func (c *ccm) mac(nonce, plaintext, additionalData []byte) []byte {
+	var b0 [16]byte
+	q := 15 - c.nonceSize
+	b0[0] = byte((c.tagSize-2)/2<<3 | (q - 1))
+	if len(additionalData) > 0 {
+		b0[0] |= 1 << 6
+	}
+	copy(b0[1:], nonce)
+	putLength(b0[1+c.nonceSize:], uint64(len(plaintext)))
+
+	x := make([]byte, 16)
+	c.block.Encrypt(x, b0[:])
+
+	if len(additionalData) > 0 {
+		var header []byte
+		n := uint64(len(additionalData))
+		switch {
+		case n < 1<<16-1<<8:
+			header = make([]byte, 2)
+			binary.BigEndian.PutUint16(header, uint16(n))
+		case n <= 1<<32-1:
+			header = make([]byte, 6)
+			header[0], header[1] = 0xff, 0xfe
+			binary.BigEndian.PutUint32(header[2:], uint32(n))
+		default:
+			header = make([]byte, 10)
+			header[0], header[1] = 0xff, 0xff
+			binary.BigEndian.PutUint64(header[2:], n)
+		}
+		c.cbcMAC(x, append(header, additionalData...))
+	}
+	c.cbcMAC(x, plaintext)
+	return x
+}
+
+// cbcMAC continues the CBC-MAC in x over data, zero padded to a multiple of
+// the block size.
+func (c *ccm) cbcMAC(x, data []byte) {
+	for len(data) > 0 {
+		n := len(data)
+		if n > 16 {
+			n = 16
+		}
+		for i := 0; i < n; i++ {
+			x[i] ^= data[i]
+		}
+		c.block.Encrypt(x, x)
+		data = data[n:]
+	}
+}
+
+// ctr encrypts or decrypts src into dst in counter mode starting at counter
+// block 1, and encrypts tag in place with counter block 0.
+func (c *ccm) ctr(nonce, dst, src, tag []byte) {
+	var a0 [16]byte
+	a0[0] = byte(15 - c.nonceSize - 1)
+	copy(a0[1:], nonce)
+
+	var s0 [16]byte
+	c.block.Encrypt(s0[:], a0[:])
+	for i := range s0 {
+		tag[i] ^= s0[i]
+	}
+
+	a0[15] = 1
+	cipher.NewCTR(c.block, a0[:]).XORKeyStream(dst, src)
+}
+
+func putLength(b []byte, n uint64) {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i] = byte(n)
+		n >>= 8
+	}
+}
+
+// sliceForAppend takes a slice and a requested number of bytes. It returns a
+// slice with the contents of the given slice followed by that many bytes and a
+// second slice that aliases into it and contains only the extra bytes.
+func sliceForAppend(in []byte, n int) (head, tail []byte) {
+	if total := len(in) + n; cap(in) >= total {
+		head = in[:total]
+	} else {
+		head = make([]byte, total)
+		copy(head, in)
+	}
+	tail = head[len(in):]
+	return
+}
@@ -0,0 +1,85 @@
+package ccm
+
+import (
+	"bytes"
+	"crypto/aes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestSealOpen(t *testing.T) {
+	// Examples from appendix C of NIST SP 800-38C and packet vector #1 of RFC 3610
+	var tests = []struct {
+		key    string
+		nonce  string
+		ad     string
+		plain  string
+		cipher string
+		tag    int
+	}{
+		{
+			"404142434445464748494a4b4c4d4e4f",
+			"10111213141516",
+			"0001020304050607",
+			"20212223",
+			"7162015b4dac255d",
+			4,
+		},
+		{
+			"404142434445464748494a4b4c4d4e4f",
+			"1011121314151617",
+			"000102030405060708090a0b0c0d0e0f",
+			"202122232425262728292a2b2c2d2e2f",
+			"d2a1f0e051ea5f62081a7792073d593d1fc64fbfaccd",
+			6,
+		},
+		{
+			"404142434445464748494a4b4c4d4e4f",
+			"101112131415161718191a1b",
+			"000102030405060708090a0b0c0d0e0f10111213",
+			"202122232425262728292a2b2c2d2e2f3031323334353637",
+			"e3b201a9f5b71a7a9b1ceaeccd97e70b6176aad9a4428aa5484392fbc1b09951",
+			8,
+		},
+		{
+			"c0c1c2c3c4c5c6c7c8c9cacbcccdcecf",
+			"00000003020100a0a1a2a3a4a5",
+			"0001020304050607",
+			"08090a0b0c0d0e0f101112131415161718191a1b1c1d1e",
+			"588c979a61c663d2f066d0c2c0f989806d5f6b61dac38417e8d12cfdf926e0",
+			8,
+		},
+	}
+
+	for i, tt := range tests {
+		k, _ := hex.DecodeString(tt.key)
+		n, _ := hex.DecodeString(tt.nonce)
+		a, _ := hex.DecodeString(tt.ad)
+		p, _ := hex.DecodeString(tt.plain)
+		c, _ := hex.DecodeString(tt.cipher)
+
+		block, _ := aes.NewCipher(k)
+		aead, err := New(block, len(n), tt.tag)
+		if err != nil {
+			t.Fatalf("%d: New returned: %s", i, err)
+		}
+
+		out := aead.Seal(nil, n, p, a)
+		if !bytes.Equal(out, c) {
+			t.Errorf("%d: seal failed: got %x wanted %x", i, out, c)
+		}
+
+		out, err = aead.Open(nil, n, c, a)
+		if err != nil {
+			t.Errorf("%d: Open returned: %s", i, err)
+		}
+		if !bytes.Equal(out, p) {
+			t.Errorf("%d: open failed: got %x wanted %x", i, out, p)
+		}
+
+		c[0] ^= 0x80
+		if _, err = aead.Open(nil, n, c, a); err == nil {
+			t.Errorf("%d: Open of tampered ciphertext should have failed", i)
+		}
+	}
+}
@@ -0,0 +1,423 @@
+// Package ecc implements constant time arithmetic on short Weierstrass
+// elliptic curves for signing, as used by SM2 and GOST R 34.10-2012
+/*
+Field elements are Montgomery residues in 64-bit limbs, and points use the
+complete projective addition formulas of Renes, Costello and Batina:
+https://eprint.iacr.org/2015/1060
+*/
+package ecc
+
+import (
+	"errors"
+	"io"
+	"math/big"
+	"math/bits"
+)
+
+// field is arithmetic modulo an odd prime m in Montgomery form with R = 2^(64n).
+// Operations take and return fully reduced elements, and their running time
+// only depends on m.
+type field struct {
+	m    []uint64
+	mInv uint64 // -m^-1 mod 2^64
+	rr   []uint64
+	one  []uint64
+	// exp is m-2, for inversion by Fermat's little theorem
+	exp *big.Int
+	// bitLen and size are the size of m in bits and bytes
+	bitLen, size int
+}
+
+func newField(m *big.Int) *field {
+	n := (m.BitLen() + 63) / 64
+	f := &field{
+		m:      toLimbs(m, n),
+		exp:    new(big.Int).Sub(m, big.NewInt(2)),
+		bitLen: m.BitLen(),
+		size:   (m.BitLen() + 7) / 8,
+	}
+	inv := uint64(1)
+	for i := 0; i < 6; i++ {
+		inv *= 2 - f.m[0]*inv
+	}
+	f.mInv = -inv
+	r := new(big.Int).Lsh(big.NewInt(1), uint(64*n))
+	f.one = toLimbs(new(big.Int).Mod(r, m), n)
+	f.rr = toLimbs(new(big.Int).Mod(new(big.Int).Mul(r, r), m), n)
+	return f
+}
+
+// toLimbs returns the little-endian 64-bit limbs of the non-negative v.
+func toLimbs(v *big.Int, n int) []uint64 {
+	return bytesToLimbs(v.Bytes(), n)
+}
+
+// bytesToLimbs returns the little-endian 64-bit limbs of the big-endian b.
+func bytesToLimbs(b []byte, n int) []uint64 {
+	z := make([]uint64, n)
+	for i := range b {
+		j := len(b) - 1 - i
+		z[j/8] |= uint64(b[i]) << (8 * uint(j%8))
+	}
+	return z
+}
+
+func (f *field) newElement() []uint64 {
+	return make([]uint64, len(f.m))
+}
+
+// mul sets z = x * y / R mod m.
+func (f *field) mul(z, x, y []uint64) {
+	n := len(f.m)
+	t := make([]uint64, n+2)
+	for i := 0; i < n; i++ {
+		var c, cc uint64
+		for j := 0; j < n; j++ {
+			hi, lo := bits.Mul64(x[j], y[i])
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[j], c = lo, hi
+		}
+		t[n], cc = bits.Add64(t[n], c, 0)
+		t[n+1] += cc
+
+		u := t[0] * f.mInv
+		hi, lo := bits.Mul64(u, f.m[0])
+		_, cc = bits.Add64(lo, t[0], 0)
+		c = hi + cc
+		for j := 1; j < n; j++ {
+			hi, lo = bits.Mul64(u, f.m[j])
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[j-1], c = lo, hi
+		}
+		t[n-1], cc = bits.Add64(t[n], c, 0)
+		t[n], t[n+1] = t[n+1]+cc, 0
+	}
+	f.reduce(z, t[:n], t[n])
+}
+
+// reduce sets z = x - m if carry:x >= m, or z = x otherwise, where x < 2m.
+func (f *field) reduce(z, x []uint64, carry uint64) {
+	n := len(f.m)
+	d := make([]uint64, n)
+	var b uint64
+	for i := 0; i < n; i++ {
+		d[i], b = bits.Sub64(x[i], f.m[i], b)
+	}
+	_, b = bits.Sub64(carry, 0, b)
+	// b is 1 if x < m, keeping x
+	mask := -b
+	for i := 0; i < n; i++ {
+		z[i] = d[i] ^ (mask & (d[i] ^ x[i]))
+	}
+}
+
+// add sets z = x + y mod m.
+func (f *field) add(z, x, y []uint64) {
+	s := make([]uint64, len(f.m))
+	var c uint64
+	for i := range s {
+		s[i], c = bits.Add64(x[i], y[i], c)
+	}
+	f.reduce(z, s, c)
+}
+
+// sub sets z = x - y mod m.
+func (f *field) sub(z, x, y []uint64) {
+	n := len(f.m)
+	d := make([]uint64, n)
+	var b uint64
+	for i := 0; i < n; i++ {
+		d[i], b = bits.Sub64(x[i], y[i], b)
+	}
+	mask := -b
+	var c uint64
+	for i := 0; i < n; i++ {
+		z[i], c = bits.Add64(d[i], f.m[i]&mask, c)
+	}
+}
+
+// invert sets z = x^-1 mod m, or 0 if x is 0.
+func (f *field) invert(z, x []uint64) {
+	r := f.newElement()
+	copy(r, f.one)
+	// The exponent is public, so branching on its bits is constant time in x
+	for i := f.exp.BitLen() - 1; i >= 0; i-- {
+		f.mul(r, r, r)
+		if f.exp.Bit(i) == 1 {
+			f.mul(r, r, x)
+		}
+	}
+	copy(z, r)
+}
+
+// toMont returns x * R mod m for x < m.
+func (f *field) toMont(x []uint64) []uint64 {
+	z := f.newElement()
+	f.mul(z, x, f.rr)
+	return z
+}
+
+// fromMont returns x / R mod m.
+func (f *field) fromMont(x []uint64) []uint64 {
+	one := f.newElement()
+	one[0] = 1
+	z := f.newElement()
+	f.mul(z, x, one)
+	return z
+}
+
+// bytes returns the big-endian encoding of x / R mod m, size bytes long.
+func (f *field) bytes(x []uint64) []byte {
+	v := f.fromMont(x)
+	b := make([]byte, f.size)
+	for i := range b {
+		j := len(b) - 1 - i
+		b[i] = byte(v[j/8] >> (8 * uint(j%8)))
+	}
+	return b
+}
+
+// isZero returns 1 if x is 0, or 0 otherwise.
+func isZero(x []uint64) int {
+	var acc uint64
+	for _, v := range x {
+		acc |= v
+	}
+	return int(1 ^ ((acc | -acc) >> 63))
+}
+
+// less returns 1 if x < y, or 0 otherwise.
+func less(x, y []uint64) int {
+	var b uint64
+	for i := range x {
+		_, b = bits.Sub64(x[i], y[i], b)
+	}
+	return int(b)
+}
+
+// cmov sets z = x if c is 1, leaving z unchanged if c is 0.
+func cmov(z, x []uint64, c int) {
+	mask := -uint64(c)
+	for i := range z {
+		z[i] ^= mask & (z[i] ^ x[i])
+	}
+}
+
+// Curve is an elliptic curve y^2 = x^3 + ax + b over GF(p) with a base point
+// G of prime order n.
+type Curve struct {
+	p, n   *field
+	a, b3  []uint64
+	gx, gy []uint64
+}
+
+// NewCurve returns the curve with the given parameters.
+func NewCurve(p, n, a, b, gx, gy *big.Int) *Curve {
+	c := &Curve{p: newField(p), n: newField(n)}
+	limbs := len(c.p.m)
+	c.a = c.p.toMont(toLimbs(a, limbs))
+	c.b3 = c.p.toMont(toLimbs(new(big.Int).Mod(new(big.Int).Mul(b, big.NewInt(3)), p), limbs))
+	c.gx = c.p.toMont(toLimbs(gx, limbs))
+	c.gy = c.p.toMont(toLimbs(gy, limbs))
+	return c
+}
+
+// point is a point in projective coordinates (X:Y:Z), with x = X/Z and
+// y = Y/Z. The point at infinity is (0:1:0).
+type point struct {
+	x, y, z []uint64
+}
+
+func (c *Curve) newPoint() *point {
+	p := &point{c.p.newElement(), c.p.newElement(), c.p.newElement()}
+	copy(p.y, c.p.one)
+	return p
+}
+
+// add sets r = p1 + p2, using algorithm 1 of Renes, Costello and Batina,
+// which is complete for any a and handles doubling and the point at infinity.
+func (c *Curve) add(r, p1, p2 *point) {
+	f := c.p
+	t0, t1, t2 := f.newElement(), f.newElement(), f.newElement()
+	t3, t4, t5 := f.newElement(), f.newElement(), f.newElement()
+	x3, y3, z3 := f.newElement(), f.newElement(), f.newElement()
+
+	f.mul(t0, p1.x, p2.x)
+	f.mul(t1, p1.y, p2.y)
+	f.mul(t2, p1.z, p2.z)
+	f.add(t3, p1.x, p1.y)
+	f.add(t4, p2.x, p2.y)
+	f.mul(t3, t3, t4)
+	f.add(t4, t0, t1)
+	f.sub(t3, t3, t4)
+	f.add(t4, p1.x, p1.z)
+	f.add(t5, p2.x, p2.z)
+	f.mul(t4, t4, t5)
+	f.add(t5, t0, t2)
+	f.sub(t4, t4, t5)
+	f.add(t5, p1.y, p1.z)
+	f.add(x3, p2.y, p2.z)
+	f.mul(t5, t5, x3)
+	f.add(x3, t1, t2)
+	f.sub(t5, t5, x3)
+	f.mul(z3, c.a, t4)
+	f.mul(x3, c.b3, t2)
+	f.add(z3, x3, z3)
+	f.sub(x3, t1, z3)
+	f.add(z3, t1, z3)
+	f.mul(y3, x3, z3)
+	f.add(t1, t0, t0)
+	f.add(t1, t1, t0)
+	f.mul(t2, c.a, t2)
+	f.mul(t4, c.b3, t4)
+	f.add(t1, t1, t2)
+	f.sub(t2, t0, t2)
+	f.mul(t2, c.a, t2)
+	f.add(t4, t4, t2)
+	f.mul(t0, t1, t4)
+	f.add(y3, y3, t0)
+	f.mul(t0, t5, t4)
+	f.mul(x3, t3, x3)
+	f.sub(x3, x3, t0)
+	f.mul(t0, t3, t1)
+	f.mul(z3, t5, z3)
+	f.add(z3, z3, t0)
+
+	r.x, r.y, r.z = x3, y3, z3
+}
+
+// ScalarBaseMult returns k*G. Its running time does not depend on k.
+func (c *Curve) ScalarBaseMult(k *Scalar) (x, y *big.Int) {
+	g := &point{c.gx, c.gy, c.p.newElement()}
+	copy(g.z, c.p.one)
+
+	// table[i] = i*G for a fixed window of 4 bits
+	var table [16]*point
+	table[0] = c.newPoint()
+	for i := 1; i < len(table); i++ {
+		table[i] = c.newPoint()
+		c.add(table[i], table[i-1], g)
+	}
+
+	r := c.newPoint()
+	t := c.newPoint()
+	for _, b := range c.n.bytes(k.v) {
+		for _, w := range [2]int{int(b >> 4), int(b & 0xf)} {
+			for i := 0; i < 4; i++ {
+				c.add(r, r, r)
+			}
+			for i, p := range table {
+				eq := isZero([]uint64{uint64(i ^ w)})
+				cmov(t.x, p.x, eq)
+				cmov(t.y, p.y, eq)
+				cmov(t.z, p.z, eq)
+			}
+			c.add(r, r, t)
+		}
+	}
+
+	zInv := c.p.newElement()
+	c.p.invert(zInv, r.z)
+	c.p.mul(r.x, r.x, zInv)
+	c.p.mul(r.y, r.y, zInv)
+	return new(big.Int).SetBytes(c.p.bytes(r.x)), new(big.Int).SetBytes(c.p.bytes(r.y))
+}
+
+// Scalar is an integer modulo the order n of a curve. Its operations run in
+// constant time.
+type Scalar struct {
+	f *field
+	v []uint64
+}
+
+// NewScalar returns the scalar 0.
+func (c *Curve) NewScalar() *Scalar {
+	return &Scalar{c.n, c.n.newElement()}
+}
+
+var errScalarRange = errors.New("ecc: scalar out of range")
+
+// SetBytes sets s to the big-endian b, which must be less than n.
+func (s *Scalar) SetBytes(b []byte) (*Scalar, error) {
+	if len(b) > 8*len(s.f.m) {
+		return nil, errScalarRange
+	}
+	v := bytesToLimbs(b, len(s.f.m))
+	if less(v, s.f.m) == 0 {
+		return nil, errScalarRange
+	}
+	s.v = s.f.toMont(v)
+	return s, nil
+}
+
+// SetBigInt sets s to v, which must be non-negative and less than n.
+func (s *Scalar) SetBigInt(v *big.Int) (*Scalar, error) {
+	if v.Sign() < 0 {
+		return nil, errScalarRange
+	}
+	return s.SetBytes(v.Bytes())
+}
+
+// Bytes returns the big-endian encoding of s, the size of n.
+func (s *Scalar) Bytes() []byte {
+	return s.f.bytes(s.v)
+}
+
+// BigInt returns s as a big.Int.
+func (s *Scalar) BigInt() *big.Int {
+	return new(big.Int).SetBytes(s.Bytes())
+}
+
+// IsZero returns 1 if s is 0, or 0 otherwise.
+func (s *Scalar) IsZero() int {
+	return isZero(s.v)
+}
+
+// Add sets s = x + y mod n.
+func (s *Scalar) Add(x, y *Scalar) *Scalar {
+	s.f.add(s.v, x.v, y.v)
+	return s
+}
+
+// Sub sets s = x - y mod n.
+func (s *Scalar) Sub(x, y *Scalar) *Scalar {
+	s.f.sub(s.v, x.v, y.v)
+	return s
+}
+
+// Mul sets s = x * y mod n.
+func (s *Scalar) Mul(x, y *Scalar) *Scalar {
+	s.f.mul(s.v, x.v, y.v)
+	return s
+}
+
+// Invert sets s = x^-1 mod n, or 0 if x is 0.
+func (s *Scalar) Invert(x *Scalar) *Scalar {
+	s.f.invert(s.v, x.v)
+	return s
+}
+
+// RandomScalar returns a uniformly random scalar in [1, n-1].
+func (c *Curve) RandomScalar(rand io.Reader) (*Scalar, error) {
+	b := make([]byte, c.n.size)
+	// Mask off the bits above the size of n, so that fewer attempts are rejected
+	topBits := uint(c.n.bitLen % 8)
+	for {
+		if _, err := io.ReadFull(rand, b); err != nil {
+			return nil, err
+		}
+		if topBits != 0 {
+			b[0] &= 1<<topBits - 1
+		}
+		k, err := c.NewScalar().SetBytes(b)
+		if err == nil && k.IsZero() == 0 {
+			return k, nil
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package ecc
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func mustHex(s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic(s)
+	}
+	return v
+}
+
+type testCurve struct {
+	name               string
+	p, n, a, b, gx, gy *big.Int
+}
+
+var testCurves = []testCurve{
+	{
+		// sm2p256v1, with a = p-3
+		name: "SM2",
+		p:    mustHex("FFFFFFFEFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF00000000FFFFFFFFFFFFFFFF"),
+		n:    mustHex("FFFFFFFEFFFFFFFFFFFFFFFFFFFFFFFF7203DF6B21C6052B53BBF40939D54123"),
+		a:    mustHex("FFFFFFFEFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF00000000FFFFFFFFFFFFFFFC"),
+		b:    mustHex("28E9FA9E9D9F5E344D5A9E4BCF6509A7F39789F515AB8F92DDBCBD414D940E93"),
+		gx:   mustHex("32C4AE2C1F1981195F9904466A39C9948FE30BBFF2660BE1715A4589334C74C7"),
+		gy:   mustHex("BC3736A2F4F6779C59BDCEE36B692153D0A9877CC62A474002DF32E52139F0A0"),
+	},
+	{
+		// id-tc26-gost-3410-2012-256-paramSetA, with a cofactor of 4
+		name: "GOST 256A",
+		p:    mustHex("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFD97"),
+		n:    mustHex("400000000000000000000000000000000FD8CDDFC87B6635C115AF556C360C67"),
+		a:    mustHex("C2173F1513981673AF4892C23035A27CE25E2013BF95AA33B22C656F277E7335"),
+		b:    mustHex("295F9BAE7428ED9CCC20E7C359A9D41A22FCCD9108E17BF7BA9337A6F8AE9513"),
+		gx:   mustHex("91E38443A5E82C0D880923425712B2BB658B9196932E02C78B2582FE742DAA28"),
+		gy:   mustHex("32879423AB1A0375895786C4BB46E9565FDE0B5344766740AF268ADB32322E5C"),
+	},
+	{
+		// id-tc26-gost-3410-2012-512-paramSetB
+		name: "GOST 512B",
+		p:    mustHex("8000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000006F"),
+		n:    mustHex("800000000000000000000000000000000000000000000000000000000000000149A1EC142565A545ACFDB77BD9D40CFA8B996712101BEA0EC6346C54374F25BD"),
+		a:    mustHex("8000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000006C"),
+		b:    mustHex("687D1B459DC841457E3E06CF6F5E2517B97C7D614AF138BCBF85DC806C4B289F3E965D2DB1416D217F8B276FAD1AB69C50F78BEE1FA3106EFB8CCBC7C5140116"),
+		gx:   mustHex("2"),
+		gy:   mustHex("1A8F7EDA389B094C2C071E3647A8940F3C123B697578C213BE6DD9E6C8EC7335DCB228FD1EDF4A39152CBCAAF8C0398828041055F94CEEEC7E21340780FE41BD"),
+	},
+}
+
+// scalarMult is a variable time affine double and add reference.
+func (tc testCurve) scalarMult(k *big.Int) (*big.Int, *big.Int) {
+	var rx, ry *big.Int
+	add := func(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+		if x1 == nil {
+			return x2, y2
+		}
+		var l *big.Int
+		if x1.Cmp(x2) == 0 {
+			l = new(big.Int).Mul(x1, x1)
+			l.Mul(l, big.NewInt(3)).Add(l, tc.a)
+			l.Mul(l, new(big.Int).ModInverse(new(big.Int).Lsh(y1, 1), tc.p))
+		} else {
+			l = new(big.Int).Sub(y2, y1)
+			d := new(big.Int).Sub(x2, x1)
+			l.Mul(l, d.ModInverse(d.Mod(d, tc.p), tc.p))
+		}
+		l.Mod(l, tc.p)
+		x3 := new(big.Int).Mul(l, l)
+		x3.Sub(x3, x1).Sub(x3, x2).Mod(x3, tc.p)
+		y3 := new(big.Int).Sub(x1, x3)
+		y3.Mul(y3, l).Sub(y3, y1).Mod(y3, tc.p)
+		return x3, y3
+	}
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		if rx != nil {
+			rx, ry = add(rx, ry, rx, ry)
+		}
+		if k.Bit(i) == 1 {
+			rx, ry = add(rx, ry, tc.gx, tc.gy)
+		}
+	}
+	return rx, ry
+}
+
+func TestScalarBaseMult(t *testing.T) {
+	for _, tc := range testCurves {
+		c := NewCurve(tc.p, tc.n, tc.a, tc.b, tc.gx, tc.gy)
+		scalars := []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(15), big.NewInt(16), new(big.Int).Sub(tc.n, big.NewInt(1))}
+		for i := 0; i < 8; i++ {
+			k, err := rand.Int(rand.Reader, tc.n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if k.Sign() != 0 {
+				scalars = append(scalars, k)
+			}
+		}
+		for _, k := range scalars {
+			s, err := c.NewScalar().SetBigInt(k)
+			if err != nil {
+				t.Fatalf("%s: SetBigInt returned: %s", tc.name, err)
+			}
+			x, y := c.ScalarBaseMult(s)
+			wantX, wantY := tc.scalarMult(k)
+			if x.Cmp(wantX) != 0 || y.Cmp(wantY) != 0 {
+				t.Errorf("%s: %x*G = (%x, %x), wanted (%x, %x)", tc.name, k, x, y, wantX, wantY)
+			}
+		}
+	}
+}
+
+func TestScalarArithmetic(t *testing.T) {
+	for _, tc := range testCurves {
+		c := NewCurve(tc.p, tc.n, tc.a, tc.b, tc.gx, tc.gy)
+		for i := 0; i < 16; i++ {
+			x, _ := rand.Int(rand.Reader, tc.n)
+			y, _ := rand.Int(rand.Reader, tc.n)
+			sx, _ := c.NewScalar().SetBigInt(x)
+			sy, _ := c.NewScalar().SetBigInt(y)
+
+			check := func(op string, got *Scalar, want *big.Int) {
+				want.Mod(want, tc.n)
+				if got.BigInt().Cmp(want) != 0 {
+					t.Errorf("%s: %s of %x and %x = %x, wanted %x", tc.name, op, x, y, got.BigInt(), want)
+				}
+			}
+			check("sum", c.NewScalar().Add(sx, sy), new(big.Int).Add(x, y))
+			check("difference", c.NewScalar().Sub(sx, sy), new(big.Int).Sub(x, y))
+			check("product", c.NewScalar().Mul(sx, sy), new(big.Int).Mul(x, y))
+			if x.Sign() != 0 {
+				check("inverse", c.NewScalar().Invert(sx), new(big.Int).ModInverse(x, tc.n))
+			}
+		}
+
+		if _, err := c.NewScalar().SetBigInt(tc.n); err == nil {
+			t.Errorf("%s: expected an error for n", tc.name)
+		}
+		if c.NewScalar().IsZero() != 1 {
+			t.Errorf("%s: NewScalar is not zero", tc.name)
+		}
+		k, err := c.RandomScalar(rand.Reader)
+		if err != nil {
+			t.Fatalf("%s: RandomScalar returned: %s", tc.name, err)
+		}
+		if k.IsZero() != 0 || k.BigInt().Cmp(tc.n) >= 0 {
+			t.Errorf("%s: invalid random scalar %x", tc.name, k.Bytes())
+		}
+	}
+}
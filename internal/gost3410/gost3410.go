@@ -0,0 +1,226 @@
+// Package gost3410 implements the GOST R 34.10-2012 digital signature algorithm
+/*
+https://tools.ietf.org/html/rfc7091
+GOST R 34.10-2012
+*/
+package gost3410
+
+import (
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/nvx/pkcs8/internal/ecc"
+)
+
+// Curve is an elliptic curve y^2 = x^3 + ax + b over GF(p) with a base point
+// (x, y) of prime order q.
+type Curve struct {
+	// Size is the size in bytes of field elements, private keys and signature halves
+	Size int
+
+	P, Q, A, B, X, Y *big.Int
+
+	// signer is the curve for the constant time operations on secret scalars
+	signer *ecc.Curve
+}
+
+func mustHex(s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("gost3410: invalid curve constant " + s)
+	}
+	return v
+}
+
+func newCurve(bits int, p, q, a, b, x, y string) *Curve {
+	c := &Curve{
+		Size: bits / 8,
+		P:    mustHex(p),
+		Q:    mustHex(q),
+		A:    mustHex(a),
+		B:    mustHex(b),
+		X:    mustHex(x),
+		Y:    mustHex(y),
+	}
+	if !c.IsOnCurve(c.X, c.Y) {
+		panic("gost3410: base point is not on the curve")
+	}
+	c.signer = ecc.NewCurve(c.P, c.Q, c.A, c.B, c.X, c.Y)
+	return c
+}
+
+// IsOnCurve reports whether the point (x, y) is on the curve
+func (c *Curve) IsOnCurve(x, y *big.Int) bool {
+	if x.Sign() < 0 || x.Cmp(c.P) >= 0 || y.Sign() < 0 || y.Cmp(c.P) >= 0 {
+		return false
+	}
+	// y^2 = x^3 + ax + b
+	lhs := new(big.Int).Mul(y, y)
+	lhs.Mod(lhs, c.P)
+	rhs := new(big.Int).Mul(x, x)
+	rhs.Add(rhs, c.A)
+	rhs.Mul(rhs, x)
+	rhs.Add(rhs, c.B)
+	rhs.Mod(rhs, c.P)
+	return lhs.Cmp(rhs) == 0
+}
+
+// add returns the sum of two affine points, nil coordinates represent the point at infinity
+func (c *Curve) add(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	if x1 == nil {
+		return x2, y2
+	}
+	if x2 == nil {
+		return x1, y1
+	}
+	lambda := new(big.Int)
+	if x1.Cmp(x2) == 0 {
+		// y1 == -y2 gives the point at infinity
+		if sum := new(big.Int).Add(y1, y2); sum.Sign() == 0 || sum.Cmp(c.P) == 0 {
+			return nil, nil
+		}
+		// lambda = (3x^2 + a) / 2y
+		num := new(big.Int).Mul(x1, x1)
+		num.Mul(num, big.NewInt(3))
+		num.Add(num, c.A)
+		den := new(big.Int).Lsh(y1, 1)
+		den.ModInverse(den, c.P)
+		lambda.Mul(num, den)
+	} else {
+		// lambda = (y2 - y1) / (x2 - x1)
+		num := new(big.Int).Sub(y2, y1)
+		den := new(big.Int).Sub(x2, x1)
+		den.Mod(den, c.P)
+		den.ModInverse(den, c.P)
+		lambda.Mul(num, den)
+	}
+	lambda.Mod(lambda, c.P)
+
+	x3 := new(big.Int).Mul(lambda, lambda)
+	x3.Sub(x3, x1)
+	x3.Sub(x3, x2)
+	x3.Mod(x3, c.P)
+	y3 := new(big.Int).Sub(x1, x3)
+	y3.Mul(y3, lambda)
+	y3.Sub(y3, y1)
+	y3.Mod(y3, c.P)
+	return x3, y3
+}
+
+// ScalarMult returns k*(x, y). It is variable time, and only used with public
+// scalars to verify signatures.
+func (c *Curve) ScalarMult(x, y, k *big.Int) (*big.Int, *big.Int) {
+	var rx, ry *big.Int
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		rx, ry = c.add(rx, ry, rx, ry)
+		if k.Bit(i) == 1 {
+			rx, ry = c.add(rx, ry, x, y)
+		}
+	}
+	return rx, ry
+}
+
+// ScalarBaseMult returns k*G where G is the base point. It is variable time,
+// and only used with public scalars to verify signatures.
+func (c *Curve) ScalarBaseMult(k *big.Int) (*big.Int, *big.Int) {
+	return c.ScalarMult(c.X, c.Y, k)
+}
+
+// GenerateKey returns a random private key d and its public key (x, y)
+func (c *Curve) GenerateKey(rand io.Reader) (d, x, y *big.Int, err error) {
+	k, err := c.signer.RandomScalar(rand)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	x, y = c.signer.ScalarBaseMult(k)
+	return k.BigInt(), x, y, nil
+}
+
+// hashToInt interprets the digest as a little-endian integer e mod q, e != 0
+func (c *Curve) hashToInt(digest []byte) *big.Int {
+	be := make([]byte, len(digest))
+	for i := range digest {
+		be[len(digest)-1-i] = digest[i]
+	}
+	e := new(big.Int).SetBytes(be)
+	e.Mod(e, c.Q)
+	if e.Sign() == 0 {
+		e.SetInt64(1)
+	}
+	return e
+}
+
+func (c *Curve) pad(v *big.Int) []byte {
+	b := v.Bytes()
+	out := make([]byte, c.Size)
+	copy(out[c.Size-len(b):], b)
+	return out
+}
+
+// Sign signs the digest, a GOST R 34.11-2012 hash in the byte order it is
+// produced in, with the private key d. The signature is s || r, each big-endian
+// and Size bytes long, as in RFC 4491. The operations on the private key and
+// the nonce are constant time.
+func (c *Curve) Sign(rand io.Reader, d *big.Int, digest []byte) ([]byte, error) {
+	ds, err := c.signer.NewScalar().SetBigInt(d)
+	if err != nil || ds.IsZero() == 1 {
+		return nil, errors.New("gost3410: invalid private key")
+	}
+	e, _ := c.signer.NewScalar().SetBigInt(c.hashToInt(digest))
+	for {
+		k, err := c.signer.RandomScalar(rand)
+		if err != nil {
+			return nil, err
+		}
+		x, _ := c.signer.ScalarBaseMult(k)
+		r := new(big.Int).Mod(x, c.Q)
+		if r.Sign() == 0 {
+			continue
+		}
+		// s = (rd + ke) mod q
+		rs, _ := c.signer.NewScalar().SetBigInt(r)
+		s := c.signer.NewScalar().Mul(rs, ds)
+		s.Add(s, k.Mul(k, e))
+		if s.IsZero() == 1 {
+			continue
+		}
+		return append(c.pad(s.BigInt()), c.pad(r)...), nil
+	}
+}
+
+// Verify reports whether sig is a valid signature of digest by the public key (x, y)
+func (c *Curve) Verify(x, y *big.Int, digest, sig []byte) bool {
+	if len(sig) != 2*c.Size || !c.IsOnCurve(x, y) {
+		return false
+	}
+	s := new(big.Int).SetBytes(sig[:c.Size])
+	r := new(big.Int).SetBytes(sig[c.Size:])
+	if r.Sign() <= 0 || s.Sign() <= 0 || r.Cmp(c.Q) >= 0 || s.Cmp(c.Q) >= 0 {
+		return false
+	}
+	v := new(big.Int).ModInverse(c.hashToInt(digest), c.Q)
+	z1 := new(big.Int).Mul(s, v)
+	z1.Mod(z1, c.Q)
+	z2 := new(big.Int).Mul(r, v)
+	z2.Neg(z2)
+	z2.Mod(z2, c.Q)
+
+	x1, y1 := c.ScalarBaseMult(z1)
+	x2, y2 := c.ScalarMult(x, y, z2)
+	cx, _ := c.add(x1, y1, x2, y2)
+	if cx == nil {
+		return false
+	}
+	return cx.Mod(cx, c.Q).Cmp(r) == 0
+}
+
+// PublicKey returns the public key for the private key d, in constant time
+func (c *Curve) PublicKey(d *big.Int) (x, y *big.Int, err error) {
+	if d.Sign() <= 0 || d.Cmp(c.Q) >= 0 {
+		return nil, nil, errors.New("gost3410: invalid private key")
+	}
+	k, _ := c.signer.NewScalar().SetBigInt(d)
+	x, y = c.signer.ScalarBaseMult(k)
+	return x, y, nil
+}
@@ -0,0 +1,91 @@
+package gost3410
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+var curves = map[string]*Curve{
+	"256A": Curve256A,
+	"256B": Curve256B,
+	"256C": Curve256C,
+	"256D": Curve256D,
+	"512A": Curve512A,
+	"512B": Curve512B,
+	"512C": Curve512C,
+}
+
+func TestCurves(t *testing.T) {
+	for name, c := range curves {
+		// q*G is the point at infinity
+		if x, _ := c.ScalarBaseMult(c.Q); x != nil {
+			t.Errorf("%s: base point does not have order q", name)
+		}
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	for name, c := range curves {
+		d, x, y, err := c.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatalf("%s: GenerateKey returned: %s", name, err)
+		}
+		if wantX, wantY := c.ScalarBaseMult(d); x.Cmp(wantX) != 0 || y.Cmp(wantY) != 0 {
+			t.Errorf("%s: public key does not match the private key", name)
+		}
+		digest := make([]byte, c.Size)
+		rand.Read(digest)
+		sig, err := c.Sign(rand.Reader, d, digest)
+		if err != nil {
+			t.Fatalf("%s: Sign returned: %s", name, err)
+		}
+		if !c.Verify(x, y, digest, sig) {
+			t.Errorf("%s: Verify failed", name)
+		}
+		digest[0] ^= 1
+		if c.Verify(x, y, digest, sig) {
+			t.Errorf("%s: Verify should have failed", name)
+		}
+	}
+}
+
+func TestVerifyKnownSignature(t *testing.T) {
+	// Signatures over the digest 00 01 02 ... produced by an independent implementation
+	d, _ := new(big.Int).SetString("7A929ADE789BB9BE10ED359DD39A72C11B60961F49397EEE1D19CE9891EC3B28", 16)
+	for _, tt := range []struct {
+		c    *Curve
+		x, y string
+		sig  string
+	}{
+		{
+			c:   Curve256A,
+			x:   "99C3DF265EA59350640BA69D1DE04418AF3FEA03EC0F85F2DD84E8BED4952774",
+			y:   "E218631A69C47C122E2D516DA1C09E6BD19344D94389D1F16C0C4D4DCF96F578",
+			sig: "395362d2d78bb9fe21b77f76a3c7c42b8088fe7187a7fe157bc1ad633a555f71206199d757ffba2447f68426789a3dbe9085eb8544ab6c842acca7141d334eee",
+		},
+		{
+			c:   Curve512C,
+			x:   "2702F6B8CC0717D37DF49A9F9FE8C755FABA5D8A48FB05AA8007EBB9656836E2312CDA480B3238BB4281EA25608CEA04A43AC1F0E96028EECA4E20BF9637C41A",
+			y:   "6AA433A2A902D63129F8A9BE4FDEBDA3A37F8A3A971F78637AFC23172E4591FBC841A03DA1D51A0E6ADC83107CC9126B3629AB582D708949C91B6DCC3BDE1AA2",
+			sig: "134210f1005100064fc120742d388b7dea68edb60fe08beaad33b66d72f9b445adb10a6ace5f799692d271241e01446ef8f4c6b419b20994df3877ab6545aa5009178833d13d351c68129c8a516aa765171d94c8704966480089ffc79fb807960bd1295bc8bb2ed1ecebf9ce39fff9ca49ed3ffe17dcbf5f4363c963f62add5a",
+		},
+	} {
+		x, y, err := tt.c.PublicKey(new(big.Int).Mod(d, tt.c.Q))
+		if err != nil {
+			t.Fatalf("PublicKey returned: %s", err)
+		}
+		if x.Cmp(mustHex(tt.x)) != 0 || y.Cmp(mustHex(tt.y)) != 0 {
+			t.Errorf("%d: public key mismatch", tt.c.Size)
+		}
+		digest := make([]byte, tt.c.Size)
+		for i := range digest {
+			digest[i] = byte(i)
+		}
+		sig, _ := hex.DecodeString(tt.sig)
+		if !tt.c.Verify(x, y, digest, sig) {
+			t.Errorf("%d: Verify failed", tt.c.Size)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package kuznyechik
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	// Example from appendix A.1 of GOST R 34.12-2015 (RFC 7801 section 5.5)
+	k, _ := hex.DecodeString("8899aabbccddeeff0011223344556677fedcba98765432100123456789abcdef")
+	p, _ := hex.DecodeString("1122334455667700ffeeddccbbaa9988")
+	c, _ := hex.DecodeString("7f679d90bebc24305a468d42b9d4edcd")
+
+	b, err := New(k)
+	if err != nil {
+		t.Fatalf("New returned: %s", err)
+	}
+
+	var dst [BlockSize]byte
+
+	b.Encrypt(dst[:], p)
+	if !bytes.Equal(dst[:], c) {
+		t.Errorf("encrypt failed: got % 2x wanted % 2x\n", dst, c)
+	}
+
+	b.Decrypt(dst[:], c)
+	if !bytes.Equal(dst[:], p) {
+		t.Errorf("decrypt failed: got % 2x wanted % 2x\n", dst, p)
+	}
+}
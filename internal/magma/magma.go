@@ -0,0 +1,97 @@
+// Package magma implements the GOST R 34.12-2015 64-bit block cipher (Magma)
+/*
+https://tools.ietf.org/html/rfc8891
+GOST R 34.12-2015
+*/
+package magma
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"math/bits"
+	"strconv"
+)
+
+// BlockSize is the magma block size in bytes
+const BlockSize = 8
+
+// KeySize is the magma key size in bytes
+const KeySize = 32
+
+type magmaCipher struct {
+	k [8]uint32
+}
+
+// KeySizeError is returned when the key is not 32 bytes long
+type KeySizeError int
+
+func (k KeySizeError) Error() string {
+	return "magma: invalid key size " + strconv.Itoa(int(k))
+}
+
+// New returns a new magma cipher with the given 256-bit key
+func New(key []byte) (cipher.Block, error) {
+	if len(key) != KeySize {
+		return nil, KeySizeError(len(key))
+	}
+	c := &magmaCipher{}
+	for i := range c.k {
+		c.k[i] = binary.BigEndian.Uint32(key[4*i:])
+	}
+	return c, nil
+}
+
+func (*magmaCipher) BlockSize() int { return BlockSize }
+
+// pi are the substitutions from id-tc26-gost-28147-param-Z, pi[0] applies to the least significant nibble
+var pi = [8][16]byte{
+	{12, 4, 6, 2, 10, 5, 11, 9, 14, 8, 13, 7, 0, 3, 15, 1},
+	{6, 8, 2, 3, 9, 10, 5, 12, 1, 14, 4, 7, 11, 13, 0, 15},
+	{11, 3, 5, 8, 2, 15, 10, 13, 14, 1, 7, 4, 12, 9, 6, 0},
+	{12, 8, 2, 1, 13, 4, 15, 6, 7, 0, 10, 5, 3, 14, 9, 11},
+	{7, 15, 5, 10, 8, 1, 6, 13, 0, 9, 3, 14, 11, 4, 2, 12},
+	{5, 13, 15, 6, 9, 2, 12, 10, 11, 7, 8, 1, 4, 3, 14, 0},
+	{8, 14, 2, 5, 6, 9, 1, 12, 15, 4, 11, 0, 13, 10, 3, 7},
+	{1, 7, 14, 13, 0, 5, 8, 3, 4, 15, 10, 6, 9, 12, 11, 2},
+}
+
+// g is the round function
+func g(k, a uint32) uint32 {
+	x := a + k
+	var t uint32
+	for i := uint(0); i < 8; i++ {
+		t |= uint32(pi[i][(x>>(4*i))&0xf]) << (4 * i)
+	}
+	return bits.RotateLeft32(t, 11)
+}
+
+// roundKey returns the key for round i (0-based) of encryption
+func (c *magmaCipher) roundKey(i int) uint32 {
+	if i < 24 {
+		return c.k[i%8]
+	}
+	return c.k[31-i]
+}
+
+func (c *magmaCipher) crypt(dst, src []byte, decrypt bool) {
+	a1 := binary.BigEndian.Uint32(src[0:])
+	a0 := binary.BigEndian.Uint32(src[4:])
+	for i := 0; i < 32; i++ {
+		k := c.roundKey(i)
+		if decrypt {
+			k = c.roundKey(31 - i)
+		}
+		a1, a0 = a0, g(k, a0)^a1
+	}
+	// the final round does not swap the halves
+	binary.BigEndian.PutUint32(dst[0:], a0)
+	binary.BigEndian.PutUint32(dst[4:], a1)
+}
+
+func (c *magmaCipher) Encrypt(dst, src []byte) {
+	c.crypt(dst, src, false)
+}
+
+func (c *magmaCipher) Decrypt(dst, src []byte) {
+	c.crypt(dst, src, true)
+}
@@ -0,0 +1,31 @@
+package magma
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	// Example from appendix A.2 of GOST R 34.12-2015 (RFC 8891 section 5.5)
+	k, _ := hex.DecodeString("ffeeddccbbaa99887766554433221100f0f1f2f3f4f5f6f7f8f9fafbfcfdfeff")
+	p, _ := hex.DecodeString("fedcba9876543210")
+	c, _ := hex.DecodeString("4ee901e5c2d8ca3d")
+
+	b, err := New(k)
+	if err != nil {
+		t.Fatalf("New returned: %s", err)
+	}
+
+	var dst [BlockSize]byte
+
+	b.Encrypt(dst[:], p)
+	if !bytes.Equal(dst[:], c) {
+		t.Errorf("encrypt failed: got % 2x wanted % 2x\n", dst, c)
+	}
+
+	b.Decrypt(dst[:], c)
+	if !bytes.Equal(dst[:], p) {
+		t.Errorf("decrypt failed: got % 2x wanted % 2x\n", dst, p)
+	}
+}
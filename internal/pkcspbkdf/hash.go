@@ -4,8 +4,18 @@ import (
 	"crypto/md5"  //nolint:gosec // compatibility
 	"crypto/sha1" //nolint:gosec // compatibility
 	"crypto/sha256"
+
+	"github.com/nvx/pkcs8/internal/md2"
 )
 
+const Md2Size = md2.Size
+
+// Md2Sum returns the MD2 hash of in.
+func Md2Sum(in []byte) []byte {
+	sum := md2.Sum(in)
+	return sum[:]
+}
+
 const Md5Size = md5.Size
 
 // Md5Sum returns the MD5 hash of in.
@@ -1,5 +1,8 @@
 package pkcspbkdf
 
+// PKCS5PBKDF1 implements PBKDF1 from RFC 8018 section 5.1, as used by the
+// PBES1 schemes with MD2, MD5 and SHA-1. size must not exceed the output size
+// of hash.
 func PKCS5PBKDF1(hash func([]byte) []byte, salt, password []byte, r int, size int) (key []byte) {
 	derived := make([]byte, len(password)+len(salt))
 	copy(derived, password)
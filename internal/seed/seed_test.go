@@ -0,0 +1,60 @@
+package seed
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	// Test vectors from appendix B of RFC 4269
+	var tests = []struct {
+		key    string
+		plain  string
+		cipher string
+	}{
+		{
+			"00000000000000000000000000000000",
+			"000102030405060708090a0b0c0d0e0f",
+			"5ebac6e0054e166819aff1cc6d346cdb",
+		},
+		{
+			"000102030405060708090a0b0c0d0e0f",
+			"00000000000000000000000000000000",
+			"c11f22f20140505084483597e4370f43",
+		},
+		{
+			"4706480851e61be85d74bfb3fd956185",
+			"83a2f8a288641fb9a4e9a5cc2f131c7d",
+			"ee54d13ebcae706d226bc3142cd40d4a",
+		},
+		{
+			"28dbc3bc49ffd87dcfa509b11d422be7",
+			"b41e6be2eba84a148e2eed84593c5ec7",
+			"9b9b7bfcd1813cb95d0b3618f40f5122",
+		},
+	}
+
+	for _, tt := range tests {
+		k, _ := hex.DecodeString(tt.key)
+		p, _ := hex.DecodeString(tt.plain)
+		c, _ := hex.DecodeString(tt.cipher)
+
+		b, err := New(k)
+		if err != nil {
+			t.Fatalf("New(%s) returned: %s", tt.key, err)
+		}
+
+		var dst [BlockSize]byte
+
+		b.Encrypt(dst[:], p)
+		if !bytes.Equal(dst[:], c) {
+			t.Errorf("encrypt failed: got % 2x wanted % 2x\n", dst, c)
+		}
+
+		b.Decrypt(dst[:], c)
+		if !bytes.Equal(dst[:], p) {
+			t.Errorf("decrypt failed: got % 2x wanted % 2x\n", dst, p)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+// Package sm2 implements the SM2 elliptic curve digital signature algorithm
+/*
+GB/T 32918-2016
+https://tools.ietf.org/html/draft-shen-sm2-ecdsa-02
+*/
+package sm2
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+	"sync"
+
+	"github.com/nvx/pkcs8/internal/ecc"
+	"github.com/nvx/pkcs8/internal/sm3"
+)
+
+var (
+	initOnce sync.Once
+	p256     *elliptic.CurveParams
+	// p256A is the a coefficient of the curve, which is p-3 as assumed by elliptic.CurveParams
+	p256A *big.Int
+	// p256Signer is the curve for the constant time operations on secret
+	// scalars, as elliptic.CurveParams is variable time
+	p256Signer *ecc.Curve
+)
+
+func initP256() {
+	p256 = &elliptic.CurveParams{Name: "SM2-P-256", BitSize: 256}
+	p256.P, _ = new(big.Int).SetString("FFFFFFFEFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF00000000FFFFFFFFFFFFFFFF", 16)
+	p256.N, _ = new(big.Int).SetString("FFFFFFFEFFFFFFFFFFFFFFFFFFFFFFFF7203DF6B21C6052B53BBF40939D54123", 16)
+	p256.B, _ = new(big.Int).SetString("28E9FA9E9D9F5E344D5A9E4BCF6509A7F39789F515AB8F92DDBCBD414D940E93", 16)
+	p256.Gx, _ = new(big.Int).SetString("32C4AE2C1F1981195F9904466A39C9948FE30BBFF2660BE1715A4589334C74C7", 16)
+	p256.Gy, _ = new(big.Int).SetString("BC3736A2F4F6779C59BDCEE36B692153D0A9877CC62A474002DF32E52139F0A0", 16)
+	p256A = new(big.Int).Sub(p256.P, big.NewInt(3))
+	p256Signer = ecc.NewCurve(p256.P, p256.N, p256A, p256.B, p256.Gx, p256.Gy)
+}
+
+// P256 returns the sm2p256v1 curve
+func P256() elliptic.Curve {
+	initOnce.Do(initP256)
+	return p256
+}
+
+// DefaultUID is the user identity used when none is given
+var DefaultUID = []byte("1234567812345678")
+
+// GenerateKey generates a new SM2 key pair
+func GenerateKey(rand io.Reader) (*ecdsa.PrivateKey, error) {
+	c := P256()
+	one := p256Signer.NewScalar()
+	one.SetBytes([]byte{1})
+	for {
+		d, err := p256Signer.RandomScalar(rand)
+		if err != nil {
+			return nil, err
+		}
+		// d must not be n-1 as 1+d must be invertible
+		if p256Signer.NewScalar().Add(d, one).IsZero() == 1 {
+			continue
+		}
+		priv := new(ecdsa.PrivateKey)
+		priv.Curve = c
+		priv.D = d.BigInt()
+		priv.X, priv.Y = p256Signer.ScalarBaseMult(d)
+		return priv, nil
+	}
+}
+
+// PublicKey returns the public key for the private key d, in constant time
+func PublicKey(d *big.Int) (x, y *big.Int, err error) {
+	P256()
+	k, err := p256Signer.NewScalar().SetBigInt(d)
+	if err != nil || k.IsZero() == 1 {
+		return nil, nil, errors.New("sm2: invalid private key")
+	}
+	x, y = p256Signer.ScalarBaseMult(k)
+	return x, y, nil
+}
+
+// Digest returns e = SM3(Z || msg) where Z is the hash of the signer's
+// identity and public key. If uid is empty DefaultUID is used.
+func Digest(pub *ecdsa.PublicKey, uid, msg []byte) ([]byte, error) {
+	if len(uid) == 0 {
+		uid = DefaultUID
+	}
+	if len(uid) >= 1<<13 {
+		return nil, errors.New("sm2: uid too long")
+	}
+	params := P256().Params()
+	size := (params.BitSize + 7) / 8
+	h := sm3.New()
+	var entl [2]byte
+	binary.BigEndian.PutUint16(entl[:], uint16(len(uid)*8))
+	h.Write(entl[:])
+	h.Write(uid)
+	for _, v := range []*big.Int{p256A, params.B, params.Gx, params.Gy, pub.X, pub.Y} {
+		h.Write(padBytes(v, size))
+	}
+	z := h.Sum(nil)
+
+	h.Reset()
+	h.Write(z)
+	h.Write(msg)
+	return h.Sum(nil), nil
+}
+
+// padBytes returns the big-endian encoding of v, left padded to size bytes
+func padBytes(v *big.Int, size int) []byte {
+	b := v.Bytes()
+	if len(b) >= size {
+		return b
+	}
+	out := make([]byte, size)
+	copy(out[size-len(b):], b)
+	return out
+}
+
+// Sign signs the digest e, as returned by Digest, using the private key.
+// The operations on the private key and the nonce are constant time.
+func Sign(rand io.Reader, priv *ecdsa.PrivateKey, e []byte) (r, s *big.Int, err error) {
+	n := P256().Params().N
+	d, err := p256Signer.NewScalar().SetBigInt(priv.D)
+	if err != nil {
+		return nil, nil, errors.New("sm2: invalid private key")
+	}
+	one := p256Signer.NewScalar()
+	one.SetBytes([]byte{1})
+	dPlusOneInv := p256Signer.NewScalar().Add(d, one)
+	if d.IsZero()|dPlusOneInv.IsZero() == 1 {
+		return nil, nil, errors.New("sm2: invalid private key")
+	}
+	dPlusOneInv.Invert(dPlusOneInv)
+
+	ei := new(big.Int).SetBytes(e)
+	for {
+		k, err := p256Signer.RandomScalar(rand)
+		if err != nil {
+			return nil, nil, err
+		}
+		x1, _ := p256Signer.ScalarBaseMult(k)
+
+		// r = (e + x1) mod n
+		r = new(big.Int).Add(ei, x1)
+		r.Mod(r, n)
+		rs, _ := p256Signer.NewScalar().SetBigInt(r)
+		if rs.IsZero()|p256Signer.NewScalar().Add(rs, k).IsZero() == 1 {
+			continue
+		}
+
+		// s = (1 + d)^-1 * (k - r*d) mod n
+		ss := p256Signer.NewScalar().Mul(rs, d)
+		ss.Sub(k, ss)
+		ss.Mul(ss, dPlusOneInv)
+		if ss.IsZero() == 1 {
+			continue
+		}
+		return r, ss.BigInt(), nil
+	}
+}
+
+// Verify verifies the signature r, s of the digest e, as returned by Digest, using the public key
+func Verify(pub *ecdsa.PublicKey, e []byte, r, s *big.Int) bool {
+	c := P256()
+	n := c.Params().N
+	if r.Sign() <= 0 || s.Sign() <= 0 || r.Cmp(n) >= 0 || s.Cmp(n) >= 0 {
+		return false
+	}
+	if !c.IsOnCurve(pub.X, pub.Y) {
+		return false
+	}
+	t := new(big.Int).Add(r, s)
+	t.Mod(t, n)
+	if t.Sign() == 0 {
+		return false
+	}
+	x1, y1 := c.ScalarBaseMult(s.Bytes())
+	x2, y2 := c.ScalarMult(pub.X, pub.Y, t.Bytes())
+	x, _ := c.Add(x1, y1, x2, y2)
+
+	// R = (e + x1') mod n
+	x.Add(x, new(big.Int).SetBytes(e))
+	x.Mod(x, n)
+	return x.Cmp(r) == 0
+}
@@ -0,0 +1,77 @@
+package sm2
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/asn1"
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+func TestSignVerify(t *testing.T) {
+	priv, err := GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey returned: %s", err)
+	}
+	if !P256().IsOnCurve(priv.X, priv.Y) {
+		t.Fatal("public key is not on the curve")
+	}
+	e, err := Digest(&priv.PublicKey, nil, []byte("message"))
+	if err != nil {
+		t.Fatalf("Digest returned: %s", err)
+	}
+	r, s, err := Sign(rand.Reader, priv, e)
+	if err != nil {
+		t.Fatalf("Sign returned: %s", err)
+	}
+	if !Verify(&priv.PublicKey, e, r, s) {
+		t.Error("Verify failed")
+	}
+	e[0] ^= 1
+	if Verify(&priv.PublicKey, e, r, s) {
+		t.Error("Verify should have failed")
+	}
+}
+
+func TestVerifyKnownSignature(t *testing.T) {
+	// Signature over "message" with the default uid, produced by an independent implementation
+	x, _ := new(big.Int).SetString("fe1fa3b2cd0cd9fac874c5d8cd9483fb6f06a63f14ed2c57b092bd763fc49a3f", 16)
+	y, _ := new(big.Int).SetString("ab203178d346ce38e2904002f8ac5347453a30923d6e592504110fbe1231bc24", 16)
+	pub := &ecdsa.PublicKey{Curve: P256(), X: x, Y: y}
+	der, _ := hex.DecodeString("304402204e50935d597a43de28d1954be3d8e91bf53e2af803c403b2a7cc857c2b4e2fc802207cd3bca18765375b3c8827def8872753daae0cb4f68de59c65f22dcf862216c3")
+	var sig struct {
+		R, S *big.Int
+	}
+	if _, err := asn1.Unmarshal(der, &sig); err != nil {
+		t.Fatalf("Unmarshal returned: %s", err)
+	}
+	e, err := Digest(pub, nil, []byte("message"))
+	if err != nil {
+		t.Fatalf("Digest returned: %s", err)
+	}
+	if !Verify(pub, e, sig.R, sig.S) {
+		t.Error("Verify failed")
+	}
+}
+
+func TestPublicKey(t *testing.T) {
+	priv, err := GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey returned: %s", err)
+	}
+	x, y := P256().ScalarBaseMult(priv.D.Bytes())
+	if x.Cmp(priv.X) != 0 || y.Cmp(priv.Y) != 0 {
+		t.Error("GenerateKey public key does not match the private key")
+	}
+	x, y, err = PublicKey(priv.D)
+	if err != nil {
+		t.Fatalf("PublicKey returned: %s", err)
+	}
+	if x.Cmp(priv.X) != 0 || y.Cmp(priv.Y) != 0 {
+		t.Error("PublicKey does not match the private key")
+	}
+	if _, _, err := PublicKey(P256().Params().N); err == nil {
+		t.Error("expected an error for a private key of n")
+	}
+}
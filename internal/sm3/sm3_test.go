@@ -0,0 +1,39 @@
+package sm3
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestSum(t *testing.T) {
+	// Examples from appendix A of GB/T 32905-2016
+	var tests = []struct {
+		in  string
+		out string
+	}{
+		{
+			"abc",
+			"66c7f0f462eeedd9d1f2d46bdc10e4e24167c4875cf2f7a2297da02b8f4ba8e0",
+		},
+		{
+			strings.Repeat("abcd", 16),
+			"debe9ff92275b8a138604889c18e5a4d6fdb70e5387e5765293dcba39c0c5732",
+		},
+	}
+
+	for _, tt := range tests {
+		sum := Sum([]byte(tt.in))
+		if got := hex.EncodeToString(sum[:]); got != tt.out {
+			t.Errorf("Sum(%q) = %s, wanted %s", tt.in, got, tt.out)
+		}
+
+		h := New()
+		for i := 0; i < len(tt.in); i++ {
+			h.Write([]byte{tt.in[i]})
+		}
+		if got := hex.EncodeToString(h.Sum(nil)); got != tt.out {
+			t.Errorf("New().Sum(%q) = %s, wanted %s", tt.in, got, tt.out)
+		}
+	}
+}
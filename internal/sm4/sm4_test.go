@@ -0,0 +1,41 @@
+package sm4
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	// Example 1 from appendix A of GB/T 32907-2016
+	k, _ := hex.DecodeString("0123456789abcdeffedcba9876543210")
+	p, _ := hex.DecodeString("0123456789abcdeffedcba9876543210")
+	c, _ := hex.DecodeString("681edf34d206965e86b3e94f536e4246")
+
+	b, err := New(k)
+	if err != nil {
+		t.Fatalf("New returned: %s", err)
+	}
+
+	var dst [BlockSize]byte
+
+	b.Encrypt(dst[:], p)
+	if !bytes.Equal(dst[:], c) {
+		t.Errorf("encrypt failed: got % 2x wanted % 2x\n", dst, c)
+	}
+
+	b.Decrypt(dst[:], c)
+	if !bytes.Equal(dst[:], p) {
+		t.Errorf("decrypt failed: got % 2x wanted % 2x\n", dst, p)
+	}
+
+	// Example 2: encrypting the plaintext 1,000,000 times
+	c, _ = hex.DecodeString("595298c7c6fd271f0402f804c33d3f66")
+	copy(dst[:], p)
+	for i := 0; i < 1000000; i++ {
+		b.Encrypt(dst[:], dst[:])
+	}
+	if !bytes.Equal(dst[:], c) {
+		t.Errorf("iterated encrypt failed: got % 2x wanted % 2x\n", dst, c)
+	}
+}
@@ -0,0 +1,68 @@
+package streebog
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"encoding/hex"
+	"hash"
+	"testing"
+)
+
+func reverse(b []byte) []byte {
+	r := make([]byte, len(b))
+	for i := range b {
+		r[len(b)-1-i] = b[i]
+	}
+	return r
+}
+
+func TestVectors(t *testing.T) {
+	// Examples from appendix A of GOST R 34.11-2012 (RFC 6986), given most significant byte first
+	m1, _ := hex.DecodeString("323130393837363534333231303938373635343332313039383736353433323130393837363534333231303938373635343332313039383736353433323130")
+	m2, _ := hex.DecodeString("fbe2e5f0eee3c820fbeafaebef20fffbf0e1e0f0f520e0ed20e8ece0ebe5f0f2f120fff0eeec20f120faf2fee5e2202ce8f6f3ede220e8e6eee1e8f0f2d1202ce8f0f2e5e220e5d1")
+	for i, tt := range []struct {
+		h    func() hash.Hash
+		in   []byte
+		want string
+	}{
+		{New512, m1, "486f64c1917879417fef082b3381a4e211c324f074654c38823a7b76f830ad00fa1fbae42b1285c0352f227524bc9ab16254288dd6863dccd5b9f54a1ad0541b"},
+		{New256, m1, "00557be5e584fd52a449b16b0251d05d27f94ab76cbaa6da890b59d8ef1e159d"},
+		{New512, m2, "28fbc9bada033b1460642bdcddb90c3fb3e56c497ccd0f62b8a2ad4935e85f037613966de4ee00531ae60f3b5a47f8dae06915d5f2f194996fcabf2622e6881e"},
+		{New256, m2, "508f7e553c06501d749a66fc28c6cac0b005746d97537fa85d9e40904efed29d"},
+	} {
+		h := tt.h()
+		h.Write(reverse(tt.in))
+		got := reverse(h.Sum(nil))
+		if hex.EncodeToString(got) != tt.want {
+			t.Errorf("%d: got %x, want %s", i, got, tt.want)
+		}
+
+		// byte at a time
+		h.Reset()
+		for _, b := range reverse(tt.in) {
+			h.Write([]byte{b})
+		}
+		if !bytes.Equal(reverse(h.Sum(nil)), got) {
+			t.Errorf("%d: incremental hash mismatch", i)
+		}
+	}
+}
+
+func TestHMAC(t *testing.T) {
+	// Examples from section 4.1 of RFC 7836
+	key, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f")
+	data, _ := hex.DecodeString("0126bdb87800af214341456563780100")
+	for i, tt := range []struct {
+		h    func() hash.Hash
+		want string
+	}{
+		{New256, "a1aa5f7de402d7b3d323f2991c8d4534013137010a83754fd0af6d7cd4922ed9"},
+		{New512, "a59bab22ecae19c65fbde6e5f4e9f5d8549d31f037f9df9b905500e171923a773d5f1530f2ed7e964cb2eedc29e9ad2f3afe93b2814f79f5000ffc0366c251e6"},
+	} {
+		mac := hmac.New(tt.h, key)
+		mac.Write(data)
+		if got := hex.EncodeToString(mac.Sum(nil)); got != tt.want {
+			t.Errorf("%d: got %s, want %s", i, got, tt.want)
+		}
+	}
+}
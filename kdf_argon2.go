@@ -0,0 +1,155 @@
+package pkcs8
+
+import (
+	"encoding/asn1"
+	"errors"
+
+	"golang.org/x/crypto/argon2"
+)
+
+var (
+	oidArgon2i  = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 19562, 1, 2, 2}
+	oidArgon2id = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 19562, 1, 2, 3}
+)
+
+func init() {
+	RegisterKDF(oidArgon2i, func() KDFParameters {
+		return new(argon2iParams)
+	})
+	RegisterKDF(oidArgon2id, func() KDFParameters {
+		return new(argon2idParams)
+	})
+}
+
+// argon2idParams is the Argon2-Parameters structure, in which the memory size
+// is given as a power of two number of kibibytes. Secret values and associated
+// data are not supported.
+type argon2idParams struct {
+	Salt           []byte
+	Passes         int
+	Parallelism    int
+	MemoryExponent int
+	OutputLength   int `asn1:"optional"`
+}
+
+type argon2iParams argon2idParams
+
+func (p argon2idParams) DeriveKey(password []byte, size int) (key []byte, err error) {
+	if err := p.validate(size); err != nil {
+		return nil, err
+	}
+	return argon2.IDKey(password, p.Salt, uint32(p.Passes), 1<<uint(p.MemoryExponent), uint8(p.Parallelism), uint32(size)), nil
+}
+
+func (p argon2iParams) DeriveKey(password []byte, size int) (key []byte, err error) {
+	if err := argon2idParams(p).validate(size); err != nil {
+		return nil, err
+	}
+	return argon2.Key(password, p.Salt, uint32(p.Passes), 1<<uint(p.MemoryExponent), uint8(p.Parallelism), uint32(size)), nil
+}
+
+// Argon2Limits bounds the cost of the Argon2 parameters that are accepted, so
+// that a crafted key cannot exhaust memory or CPU when it is parsed.
+type Argon2Limits struct {
+	// MaxMemory is the largest memory size in kibibytes.
+	MaxMemory uint32
+	// MaxTime is the largest number of passes.
+	MaxTime uint32
+	// MaxThreads is the largest degree of parallelism.
+	MaxThreads uint8
+}
+
+// DefaultArgon2Limits are the limits applied when parsing and encrypting keys
+// with Argon2. The defaults can be changed by the library user.
+var DefaultArgon2Limits = &Argon2Limits{
+	MaxMemory:  1 << 20,
+	MaxTime:    16,
+	MaxThreads: 16,
+}
+
+func (p argon2idParams) validate(size int) error {
+	if p.Passes < 1 || p.Parallelism < 1 || p.Parallelism > 255 || p.MemoryExponent < 3 {
+		return &MalformedInputError{Field: "Argon2 parameters"}
+	}
+	limits := DefaultArgon2Limits
+	if p.MemoryExponent > 31 || uint64(1)<<uint(p.MemoryExponent) > uint64(limits.MaxMemory) {
+		return &MalformedInputError{Field: "Argon2 memory size"}
+	}
+	if uint64(p.Passes) > uint64(limits.MaxTime) {
+		return &MalformedInputError{Field: "Argon2 passes"}
+	}
+	if p.Parallelism > int(limits.MaxThreads) {
+		return &MalformedInputError{Field: "Argon2 parallelism"}
+	}
+	if p.OutputLength != 0 && p.OutputLength != size {
+		return &MalformedInputError{Field: "Argon2 output length"}
+	}
+	return nil
+}
+
+// Argon2Opts contains options for the Argon2id key derivation function.
+// Memory is in kibibytes and must be a power of two, and the options must be
+// within DefaultArgon2Limits.
+type Argon2Opts struct {
+	SaltSize int
+	Time     uint32
+	Memory   uint32
+	Threads  uint8
+}
+
+// Argon2iOpts contains options for the Argon2i key derivation function.
+// Memory is in kibibytes and must be a power of two, and the options must be
+// within DefaultArgon2Limits.
+type Argon2iOpts Argon2Opts
+
+func (p Argon2Opts) DeriveKey(password, salt []byte, size int) (key []byte, params KDFParameters, err error) {
+	kdfParams, err := p.params(salt, size)
+	if err != nil {
+		return nil, nil, err
+	}
+	key = argon2.IDKey(password, salt, p.Time, p.Memory, p.Threads, uint32(size))
+	return key, kdfParams, nil
+}
+
+func (p Argon2iOpts) DeriveKey(password, salt []byte, size int) (key []byte, params KDFParameters, err error) {
+	kdfParams, err := Argon2Opts(p).params(salt, size)
+	if err != nil {
+		return nil, nil, err
+	}
+	key = argon2.Key(password, salt, p.Time, p.Memory, p.Threads, uint32(size))
+	return key, argon2iParams(kdfParams), nil
+}
+
+func (p Argon2Opts) params(salt []byte, size int) (argon2idParams, error) {
+	exponent := 0
+	for uint64(1)<<uint(exponent) < uint64(p.Memory) {
+		exponent++
+	}
+	params := argon2idParams{
+		Salt:           salt,
+		Passes:         int(p.Time),
+		Parallelism:    int(p.Threads),
+		MemoryExponent: exponent,
+		OutputLength:   size,
+	}
+	if 1<<uint(exponent) != p.Memory {
+		return params, errors.New("pkcs8: Argon2 memory size must be a power of two")
+	}
+	return params, params.validate(size)
+}
+
+func (p Argon2Opts) GetSaltSize() int {
+	return p.SaltSize
+}
+
+func (p Argon2iOpts) GetSaltSize() int {
+	return p.SaltSize
+}
+
+func (p Argon2Opts) OID() asn1.ObjectIdentifier {
+	return oidArgon2id
+}
+
+func (p Argon2iOpts) OID() asn1.ObjectIdentifier {
+	return oidArgon2i
+}
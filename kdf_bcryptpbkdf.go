@@ -0,0 +1,66 @@
+package pkcs8
+
+import (
+	"encoding/asn1"
+	"errors"
+
+	"github.com/nvx/pkcs8/internal/bcryptpbkdf"
+)
+
+// RegisterBcryptPBKDF registers oid as identifying bcrypt_pbkdf, so that keys
+// using it can be parsed. bcrypt_pbkdf has no registered OID, so an OID under
+// an arc controlled by the caller has to be chosen, and given to
+// BcryptPBKDFOpts when encrypting.
+func RegisterBcryptPBKDF(oid asn1.ObjectIdentifier) {
+	RegisterKDF(oid, func() KDFParameters {
+		return new(bcryptPBKDFParams)
+	})
+	algorithmNames[oid.String()] = "bcrypt_pbkdf"
+}
+
+type bcryptPBKDFParams struct {
+	Salt   []byte
+	Rounds int
+}
+
+func (p bcryptPBKDFParams) DeriveKey(password []byte, size int) (key []byte, err error) {
+	return bcryptpbkdf.Key(password, p.Salt, p.Rounds, size)
+}
+
+// BcryptPBKDFOpts contains options for the bcrypt_pbkdf key derivation
+// function used by OpenSSH and signify.
+type BcryptPBKDFOpts struct {
+	SaltSize int
+	Rounds   int
+	// Algorithm is the OID written to identify bcrypt_pbkdf, and must have
+	// been registered with RegisterBcryptPBKDF.
+	Algorithm asn1.ObjectIdentifier
+}
+
+func (p BcryptPBKDFOpts) DeriveKey(password, salt []byte, size int) (key []byte, params KDFParameters, err error) {
+	if len(p.Algorithm) == 0 {
+		return nil, nil, errors.New("pkcs8: bcrypt_pbkdf requires an algorithm OID")
+	}
+	if newParams, ok := kdfs[p.Algorithm.String()]; !ok {
+		return nil, nil, errors.New("pkcs8: bcrypt_pbkdf algorithm OID is not registered")
+	} else if _, ok := newParams().(*bcryptPBKDFParams); !ok {
+		return nil, nil, errors.New("pkcs8: algorithm OID is not registered as bcrypt_pbkdf")
+	}
+	key, err = bcryptpbkdf.Key(password, salt, p.Rounds, size)
+	if err != nil {
+		return nil, nil, err
+	}
+	params = bcryptPBKDFParams{
+		Salt:   salt,
+		Rounds: p.Rounds,
+	}
+	return key, params, nil
+}
+
+func (p BcryptPBKDFOpts) GetSaltSize() int {
+	return p.SaltSize
+}
+
+func (p BcryptPBKDFOpts) OID() asn1.ObjectIdentifier {
+	return p.Algorithm
+}
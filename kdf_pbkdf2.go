@@ -4,58 +4,107 @@ import (
 	"crypto"
 	"crypto/sha1" //nolint:gosec // compatibility
 	"crypto/sha256"
+	"crypto/sha512"
 	"crypto/x509/pkix"
 	"encoding/asn1"
 	"errors"
 	"hash"
 
+	"github.com/nvx/pkcs8/internal/sm3"
+	"github.com/nvx/pkcs8/internal/streebog"
 	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/sha3"
 )
 
 var (
-	oidPKCS5PBKDF2    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
-	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
-	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidPKCS5PBKDF2        = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidHMACWithSHA1       = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA224     = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 8}
+	oidHMACWithSHA256     = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHMACWithSHA384     = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 10}
+	oidHMACWithSHA512     = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+	oidHMACWithSHA512_224 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 12}
+	oidHMACWithSHA512_256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 13}
+	oidHMACWithSM3        = asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 401, 2}
+
+	oidHMACWithSHA3_224 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 13}
+	oidHMACWithSHA3_256 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 14}
+	oidHMACWithSHA3_384 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 15}
+	oidHMACWithSHA3_512 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 16}
+
+	oidHMACWithStreebog256 = asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 4, 1}
+	oidHMACWithStreebog512 = asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 4, 2}
 )
 
 func init() {
 	RegisterKDF(oidPKCS5PBKDF2, func() KDFParameters {
 		return new(pbkdf2Params)
 	})
+
+	RegisterPRF(oidHMACWithSHA1, sha1.New, crypto.SHA1)
+	RegisterPRF(oidHMACWithSHA224, sha256.New224, crypto.SHA224)
+	RegisterPRF(oidHMACWithSHA256, sha256.New, crypto.SHA256)
+	RegisterPRF(oidHMACWithSHA384, sha512.New384, crypto.SHA384)
+	RegisterPRF(oidHMACWithSHA512, sha512.New, crypto.SHA512)
+	RegisterPRF(oidHMACWithSHA512_224, sha512.New512_224, crypto.SHA512_224)
+	RegisterPRF(oidHMACWithSHA512_256, sha512.New512_256, crypto.SHA512_256)
+	RegisterPRF(oidHMACWithSHA3_224, sha3.New224, crypto.SHA3_224)
+	RegisterPRF(oidHMACWithSHA3_256, sha3.New256, crypto.SHA3_256)
+	RegisterPRF(oidHMACWithSHA3_384, sha3.New384, crypto.SHA3_384)
+	RegisterPRF(oidHMACWithSHA3_512, sha3.New512, crypto.SHA3_512)
+	RegisterPRF(oidHMACWithSM3, sm3.New, 0)
+	RegisterPRF(oidHMACWithStreebog256, streebog.New256, 0)
+	RegisterPRF(oidHMACWithStreebog512, streebog.New512, 0)
+}
+
+var prfs = make(map[string]func() hash.Hash)
+var prfOIDs = make(map[crypto.Hash]asn1.ObjectIdentifier)
+
+// RegisterPRF registers a function that returns a new instance of the hash
+// function used with HMAC as the PBKDF2 PRF with the given OID. This allows the
+// library to support client-provided PRFs. If h is not zero, the PRF is also
+// selected by PBKDF2Opts.HMACHash for h, otherwise it is selected by OID with
+// PBKDF2Opts.PRF.
+func RegisterPRF(oid asn1.ObjectIdentifier, newHash func() hash.Hash, h crypto.Hash) {
+	prfs[oid.String()] = newHash
+	if h != 0 {
+		prfOIDs[h] = oid
+	}
 }
 
 func newHashFromPRF(ai pkix.AlgorithmIdentifier) (func() hash.Hash, error) {
-	switch {
-	case len(ai.Algorithm) == 0 || ai.Algorithm.Equal(oidHMACWithSHA1):
+	// The PRF defaults to hmacWithSHA1
+	if len(ai.Algorithm) == 0 {
 		return sha1.New, nil
-	case ai.Algorithm.Equal(oidHMACWithSHA256):
-		return sha256.New, nil
-	default:
-		return nil, errors.New("pkcs8: unsupported hash function")
 	}
+	newHash, ok := prfs[ai.Algorithm.String()]
+	if !ok {
+		return nil, &UnsupportedAlgorithmError{Kind: "PRF", OID: ai.Algorithm}
+	}
+	return newHash, nil
 }
 
 func newPRFParamFromHash(h crypto.Hash) (pkix.AlgorithmIdentifier, error) {
-	switch h {
-	case crypto.SHA1:
-		return pkix.AlgorithmIdentifier{
-			Algorithm:  oidHMACWithSHA1,
-			Parameters: asn1.RawValue{Tag: asn1.TagNull}}, nil
-	case crypto.SHA256:
-		return pkix.AlgorithmIdentifier{
-			Algorithm:  oidHMACWithSHA256,
-			Parameters: asn1.RawValue{Tag: asn1.TagNull}}, nil
+	oid, ok := prfOIDs[h]
+	if !ok {
+		return pkix.AlgorithmIdentifier{}, errors.New("pkcs8: unsupported hash function")
 	}
-	return pkix.AlgorithmIdentifier{}, errors.New("pkcs8: unsupported hash function")
+	return pkix.AlgorithmIdentifier{
+		Algorithm:  oid,
+		Parameters: asn1.RawValue{Tag: asn1.TagNull}}, nil
 }
 
 type pbkdf2Params struct {
 	Salt           []byte
 	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
 	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
 }
 
 func (p pbkdf2Params) DeriveKey(password []byte, size int) (key []byte, err error) {
+	if p.KeyLength != 0 && p.KeyLength != size {
+		return nil, &MalformedInputError{Field: "PBKDF2 key length"}
+	}
 	h, err := newHashFromPRF(p.PRF)
 	if err != nil {
 		return nil, err
@@ -68,16 +117,47 @@ type PBKDF2Opts struct {
 	SaltSize       int
 	IterationCount int
 	HMACHash       crypto.Hash
+	// PRF selects the PRF by OID instead of HMACHash, for PRFs registered
+	// without a crypto.Hash value such as HMAC-SM3 and HMAC-Streebog.
+	PRF asn1.ObjectIdentifier
+	// IncludeKeyLength writes the optional keyLength field, which some
+	// consumers require.
+	IncludeKeyLength bool
 }
 
+// Pseudorandom functions for use with PBKDF2Opts.PRF.
+var (
+	HMACWithSM3         = oidHMACWithSM3
+	HMACWithStreebog256 = oidHMACWithStreebog256
+	HMACWithStreebog512 = oidHMACWithStreebog512
+)
+
 func (p PBKDF2Opts) DeriveKey(password, salt []byte, size int) (key []byte, params KDFParameters, err error) {
-	key = pbkdf2.Key(password, salt, p.IterationCount, size, p.HMACHash.New)
-	prfParam, err := newPRFParamFromHash(p.HMACHash)
+	var prfParam pkix.AlgorithmIdentifier
+	if p.PRF != nil {
+		prfParam = pkix.AlgorithmIdentifier{
+			Algorithm:  p.PRF,
+			Parameters: asn1.RawValue{Tag: asn1.TagNull}}
+	} else {
+		prfParam, err = newPRFParamFromHash(p.HMACHash)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	h, err := newHashFromPRF(prfParam)
 	if err != nil {
 		return nil, nil, err
 	}
-	params = pbkdf2Params{salt, p.IterationCount, prfParam}
-	return key, params, nil
+	key = pbkdf2.Key(password, salt, p.IterationCount, size, h)
+	kdfParams := pbkdf2Params{
+		Salt:           salt,
+		IterationCount: p.IterationCount,
+		PRF:            prfParam,
+	}
+	if p.IncludeKeyLength {
+		kdfParams.KeyLength = size
+	}
+	return key, kdfParams, nil
 }
 
 func (p PBKDF2Opts) GetSaltSize() int {
@@ -21,9 +21,13 @@ type scryptParams struct {
 	CostParameter            int
 	BlockSize                int
 	ParallelizationParameter int
+	KeyLength                int `asn1:"optional"`
 }
 
 func (p scryptParams) DeriveKey(password []byte, size int) (key []byte, err error) {
+	if p.KeyLength != 0 && p.KeyLength != size {
+		return nil, &MalformedInputError{Field: "scrypt key length"}
+	}
 	return scrypt.Key(password, p.Salt, p.CostParameter, p.BlockSize,
 		p.ParallelizationParameter, size)
 }
@@ -34,6 +38,9 @@ type ScryptOpts struct {
 	CostParameter            int
 	BlockSize                int
 	ParallelizationParameter int
+	// IncludeKeyLength writes the optional keyLength field, which some
+	// consumers require.
+	IncludeKeyLength bool
 }
 
 func (p ScryptOpts) DeriveKey(password, salt []byte, size int) (key []byte, params KDFParameters, err error) {
@@ -42,13 +49,16 @@ func (p ScryptOpts) DeriveKey(password, salt []byte, size int) (key []byte, para
 	if err != nil {
 		return nil, nil, err
 	}
-	params = scryptParams{
+	kdfParams := scryptParams{
 		BlockSize:                p.BlockSize,
 		CostParameter:            p.CostParameter,
 		ParallelizationParameter: p.ParallelizationParameter,
 		Salt:                     salt,
 	}
-	return key, params, nil
+	if p.IncludeKeyLength {
+		kdfParams.KeyLength = size
+	}
+	return key, kdfParams, nil
 }
 
 func (p ScryptOpts) GetSaltSize() int {
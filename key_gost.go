@@ -0,0 +1,236 @@
+package pkcs8
+
+import (
+	"crypto"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/nvx/pkcs8/internal/gost3410"
+)
+
+var (
+	oidGOST2012PublicKey256 = asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 1, 1}
+	oidGOST2012PublicKey512 = asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 1, 2}
+
+	oidStreebog256 = asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 2, 2}
+
+	oidCryptoProAParamSet    = asn1.ObjectIdentifier{1, 2, 643, 2, 2, 35, 1}
+	oidCryptoProBParamSet    = asn1.ObjectIdentifier{1, 2, 643, 2, 2, 35, 2}
+	oidCryptoProCParamSet    = asn1.ObjectIdentifier{1, 2, 643, 2, 2, 35, 3}
+	oidCryptoProXchAParamSet = asn1.ObjectIdentifier{1, 2, 643, 2, 2, 36, 0}
+	oidCryptoProXchBParamSet = asn1.ObjectIdentifier{1, 2, 643, 2, 2, 36, 1}
+)
+
+// GOST R 34.10-2012 parameter sets, as defined in RFC 7836 and RFC 9215.
+var (
+	GOST256ParamSetA = asn1.ObjectIdentifier{1, 2, 643, 7, 1, 2, 1, 1, 1}
+	GOST256ParamSetB = asn1.ObjectIdentifier{1, 2, 643, 7, 1, 2, 1, 1, 2}
+	GOST256ParamSetC = asn1.ObjectIdentifier{1, 2, 643, 7, 1, 2, 1, 1, 3}
+	GOST256ParamSetD = asn1.ObjectIdentifier{1, 2, 643, 7, 1, 2, 1, 1, 4}
+	GOST512ParamSetA = asn1.ObjectIdentifier{1, 2, 643, 7, 1, 2, 1, 2, 1}
+	GOST512ParamSetB = asn1.ObjectIdentifier{1, 2, 643, 7, 1, 2, 1, 2, 2}
+	GOST512ParamSetC = asn1.ObjectIdentifier{1, 2, 643, 7, 1, 2, 1, 2, 3}
+)
+
+func gostCurve(paramSet asn1.ObjectIdentifier) *gost3410.Curve {
+	switch {
+	case paramSet.Equal(GOST256ParamSetA):
+		return gost3410.Curve256A
+	case paramSet.Equal(GOST256ParamSetB), paramSet.Equal(oidCryptoProAParamSet), paramSet.Equal(oidCryptoProXchAParamSet):
+		return gost3410.Curve256B
+	case paramSet.Equal(GOST256ParamSetC), paramSet.Equal(oidCryptoProBParamSet):
+		return gost3410.Curve256C
+	case paramSet.Equal(GOST256ParamSetD), paramSet.Equal(oidCryptoProCParamSet), paramSet.Equal(oidCryptoProXchBParamSet):
+		return gost3410.Curve256D
+	case paramSet.Equal(GOST512ParamSetA):
+		return gost3410.Curve512A
+	case paramSet.Equal(GOST512ParamSetB):
+		return gost3410.Curve512B
+	case paramSet.Equal(GOST512ParamSetC):
+		return gost3410.Curve512C
+	}
+	return nil
+}
+
+// isCryptoProParamSet reports whether the parameter set is one of the GOST R 34.10-2001
+// CryptoPro identifiers, which RFC 9215 requires to be paired with a digest parameter set.
+func isCryptoProParamSet(paramSet asn1.ObjectIdentifier) bool {
+	for _, oid := range []asn1.ObjectIdentifier{
+		oidCryptoProAParamSet, oidCryptoProBParamSet, oidCryptoProCParamSet,
+		oidCryptoProXchAParamSet, oidCryptoProXchBParamSet,
+	} {
+		if paramSet.Equal(oid) {
+			return true
+		}
+	}
+	return false
+}
+
+// GOSTPublicKey is a GOST R 34.10-2012 public key.
+type GOSTPublicKey struct {
+	ParamSet asn1.ObjectIdentifier
+	X, Y     *big.Int
+}
+
+// GOSTPrivateKey is a GOST R 34.10-2012 private key.
+// It implements crypto.Signer, which is constant time in the private key and
+// nonce.
+type GOSTPrivateKey struct {
+	GOSTPublicKey
+	D *big.Int
+}
+
+// gostPublicKeyParameters is the GostR3410-2012-PublicKeyParameters structure from RFC 9215.
+type gostPublicKeyParameters struct {
+	PublicKeyParamSet asn1.ObjectIdentifier
+	DigestParamSet    asn1.ObjectIdentifier `asn1:"optional"`
+}
+
+// GenerateGOSTKey generates a new GOST R 34.10-2012 private key using the given parameter set.
+func GenerateGOSTKey(paramSet asn1.ObjectIdentifier, rand io.Reader) (*GOSTPrivateKey, error) {
+	curve := gostCurve(paramSet)
+	if curve == nil {
+		return nil, errors.New("pkcs8: unsupported GOST parameter set")
+	}
+	d, x, y, err := curve.GenerateKey(rand)
+	if err != nil {
+		return nil, err
+	}
+	return &GOSTPrivateKey{GOSTPublicKey{paramSet, x, y}, d}, nil
+}
+
+// Public returns the public key corresponding to priv.
+func (priv *GOSTPrivateKey) Public() crypto.PublicKey {
+	return &priv.GOSTPublicKey
+}
+
+// Sign signs digest, the GOST R 34.11-2012 hash of the message of the same size as the key,
+// and returns the signature as s || r as defined in RFC 4491.
+// opts is ignored.
+func (priv *GOSTPrivateKey) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	curve := gostCurve(priv.ParamSet)
+	if curve == nil {
+		return nil, errors.New("pkcs8: unsupported GOST parameter set")
+	}
+	return curve.Sign(rand, priv.D, digest)
+}
+
+// VerifyGOST verifies the signature sig of digest, as produced by GOSTPrivateKey.Sign, using the public key pub.
+func VerifyGOST(pub *GOSTPublicKey, digest, sig []byte) bool {
+	curve := gostCurve(pub.ParamSet)
+	if curve == nil {
+		return false
+	}
+	return curve.Verify(pub.X, pub.Y, digest, sig)
+}
+
+func isGOSTPrivateKeyInfo(info privateKeyInfo) bool {
+	return info.Algo.Algorithm.Equal(oidGOST2012PublicKey256) || info.Algo.Algorithm.Equal(oidGOST2012PublicKey512)
+}
+
+func parseGOSTPrivateKey(info privateKeyInfo) (*GOSTPrivateKey, error) {
+	var params gostPublicKeyParameters
+	if err := unmarshal(info.Algo.Parameters.FullBytes, &params); err != nil {
+		return nil, &MalformedInputError{Field: "GOST key parameters", Err: err}
+	}
+	curve := gostCurve(params.PublicKeyParamSet)
+	if curve == nil {
+		return nil, &UnsupportedAlgorithmError{Kind: "GOST parameter set", OID: params.PublicKeyParamSet}
+	}
+	if info.Algo.Algorithm.Equal(oidGOST2012PublicKey256) != (curve.Size == 32) {
+		return nil, &MalformedInputError{Field: "GOST parameter set"}
+	}
+
+	d, err := parseGOSTPrivateKeyValue(info.PrivateKey, curve.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	x, y, err := curve.PublicKey(d)
+	if err != nil {
+		return nil, &MalformedInputError{Field: "GOST private key value"}
+	}
+	return &GOSTPrivateKey{GOSTPublicKey{params.PublicKeyParamSet, x, y}, d}, nil
+}
+
+// parseGOSTPrivateKeyValue decodes the private key value. RFC 9215 specifies a
+// little-endian OCTET STRING, some implementations use an INTEGER or the bare
+// key, which is only assumed if neither DER encoding decodes.
+func parseGOSTPrivateKeyValue(der []byte, size int) (*big.Int, error) {
+	var octets []byte
+	if err := unmarshal(der, &octets); err == nil && len(octets) <= size {
+		return littleEndianToInt(octets), nil
+	}
+	d := new(big.Int)
+	if err := unmarshal(der, &d); err == nil {
+		return d, nil
+	}
+	if len(der) == size {
+		return littleEndianToInt(der), nil
+	}
+	return nil, &MalformedInputError{Field: "GOST private key"}
+}
+
+func marshalGOSTPrivateKey(priv *GOSTPrivateKey) ([]byte, error) {
+	curve := gostCurve(priv.ParamSet)
+	if curve == nil {
+		return nil, errors.New("pkcs8: unsupported GOST parameter set")
+	}
+	algo := oidGOST2012PublicKey256
+	if curve.Size == 64 {
+		algo = oidGOST2012PublicKey512
+	}
+	params := gostPublicKeyParameters{PublicKeyParamSet: priv.ParamSet}
+	if isCryptoProParamSet(priv.ParamSet) {
+		params.DigestParamSet = oidStreebog256
+	}
+	marshalledParams, err := asn1.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+
+	d := make([]byte, curve.Size)
+	dBytes := priv.D.Bytes()
+	if len(dBytes) > len(d) {
+		return nil, errors.New("pkcs8: invalid GOST private key value")
+	}
+	for i, b := range dBytes {
+		d[len(dBytes)-1-i] = b
+	}
+	key, err := asn1.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+
+	return asn1.Marshal(privateKeyInfo{
+		Algo: pkix.AlgorithmIdentifier{
+			Algorithm:  algo,
+			Parameters: asn1.RawValue{FullBytes: marshalledParams},
+		},
+		PrivateKey: key,
+	})
+}
+
+func littleEndianToInt(b []byte) *big.Int {
+	be := make([]byte, len(b))
+	for i := range b {
+		be[len(b)-1-i] = b[i]
+	}
+	return new(big.Int).SetBytes(be)
+}
+
+// ParsePKCS8PrivateKeyGOST parses encrypted/unencrypted private keys in PKCS#8 format. To parse encrypted private keys, a password of []byte type should be provided to the function as the second parameter.
+func ParsePKCS8PrivateKeyGOST(der []byte, v ...[]byte) (*GOSTPrivateKey, error) {
+	key, err := ParsePKCS8PrivateKey(der, v...)
+	if err != nil {
+		return nil, err
+	}
+	typedKey, ok := key.(*GOSTPrivateKey)
+	if !ok {
+		return nil, errors.New("key block is not of type GOST")
+	}
+	return typedKey, nil
+}
@@ -0,0 +1,154 @@
+package pkcs8
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/nvx/pkcs8/internal/sm2"
+)
+
+var (
+	oidPublicKeyECDSA = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidNamedCurveSM2  = asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 301}
+)
+
+// SM2PrivateKey is an SM2 private key on the sm2p256v1 curve, as defined in GB/T 32918.
+// It implements crypto.Signer, which is constant time in the private key and
+// nonce. Curve is a generic elliptic.CurveParams, whose methods are variable
+// time and must not be used with D.
+type SM2PrivateKey struct {
+	ecdsa.PrivateKey
+}
+
+// ecPrivateKey is the ECPrivateKey structure from RFC 5915.
+type ecPrivateKey struct {
+	Version       int
+	PrivateKey    []byte
+	NamedCurveOID asn1.ObjectIdentifier `asn1:"optional,explicit,tag:0"`
+	PublicKey     asn1.BitString        `asn1:"optional,explicit,tag:1"`
+}
+
+// GenerateSM2Key generates a new SM2 private key.
+func GenerateSM2Key(rand io.Reader) (*SM2PrivateKey, error) {
+	priv, err := sm2.GenerateKey(rand)
+	if err != nil {
+		return nil, err
+	}
+	return &SM2PrivateKey{*priv}, nil
+}
+
+// SM2Digest returns the digest of msg to be signed by the SM2 key whose public part is pub.
+// The digest covers the signer's identity uid, if uid is nil the default identity "1234567812345678" is used.
+func SM2Digest(pub *ecdsa.PublicKey, uid, msg []byte) ([]byte, error) {
+	return sm2.Digest(pub, uid, msg)
+}
+
+// Sign signs digest, as returned by SM2Digest, and returns an ASN.1 encoded signature.
+// opts is ignored.
+func (priv *SM2PrivateKey) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	r, s, err := sm2.Sign(rand, &priv.PrivateKey, digest)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(sm2Signature{r, s})
+}
+
+type sm2Signature struct {
+	R, S *big.Int
+}
+
+// VerifySM2 verifies the ASN.1 encoded signature sig of digest, as returned by SM2Digest, using the public key pub.
+func VerifySM2(pub *ecdsa.PublicKey, digest, sig []byte) bool {
+	var s sm2Signature
+	if err := unmarshal(sig, &s); err != nil {
+		return false
+	}
+	return sm2.Verify(pub, digest, s.R, s.S)
+}
+
+func isSM2PrivateKeyInfo(info privateKeyInfo) bool {
+	if !info.Algo.Algorithm.Equal(oidPublicKeyECDSA) {
+		return false
+	}
+	var namedCurve asn1.ObjectIdentifier
+	if _, err := asn1.Unmarshal(info.Algo.Parameters.FullBytes, &namedCurve); err != nil {
+		return false
+	}
+	return namedCurve.Equal(oidNamedCurveSM2)
+}
+
+func parseSM2PrivateKey(der []byte) (*SM2PrivateKey, error) {
+	var ecKey ecPrivateKey
+	if err := unmarshal(der, &ecKey); err != nil {
+		return nil, &MalformedInputError{Field: "SM2 private key", Err: err}
+	}
+	if ecKey.Version != 1 {
+		return nil, &MalformedInputError{Field: "SM2 private key version"}
+	}
+	if ecKey.NamedCurveOID != nil && !ecKey.NamedCurveOID.Equal(oidNamedCurveSM2) {
+		return nil, &MalformedInputError{Field: "SM2 private key curve"}
+	}
+
+	d := new(big.Int).SetBytes(ecKey.PrivateKey)
+	x, y, err := sm2.PublicKey(d)
+	if err != nil {
+		return nil, &MalformedInputError{Field: "SM2 private key value", Err: err}
+	}
+
+	priv := new(SM2PrivateKey)
+	priv.Curve = sm2.P256()
+	priv.D = d
+	priv.X, priv.Y = x, y
+	return priv, nil
+}
+
+func marshalSM2PrivateKey(priv *SM2PrivateKey) ([]byte, error) {
+	size := (priv.Curve.Params().BitSize + 7) / 8
+	// uncompressed point encoding
+	pub := make([]byte, 1+2*size)
+	pub[0] = 4
+	xBytes, yBytes := priv.X.Bytes(), priv.Y.Bytes()
+	copy(pub[1+size-len(xBytes):], xBytes)
+	copy(pub[1+2*size-len(yBytes):], yBytes)
+	d := make([]byte, size)
+	dBytes := priv.D.Bytes()
+	copy(d[size-len(dBytes):], dBytes)
+
+	ecKey, err := asn1.Marshal(ecPrivateKey{
+		Version:    1,
+		PrivateKey: d,
+		PublicKey:  asn1.BitString{Bytes: pub, BitLength: 8 * len(pub)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	curve, err := asn1.Marshal(oidNamedCurveSM2)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(privateKeyInfo{
+		Algo: pkix.AlgorithmIdentifier{
+			Algorithm:  oidPublicKeyECDSA,
+			Parameters: asn1.RawValue{FullBytes: curve},
+		},
+		PrivateKey: ecKey,
+	})
+}
+
+// ParsePKCS8PrivateKeySM2 parses encrypted/unencrypted private keys in PKCS#8 format. To parse encrypted private keys, a password of []byte type should be provided to the function as the second parameter.
+func ParsePKCS8PrivateKeySM2(der []byte, v ...[]byte) (*SM2PrivateKey, error) {
+	key, err := ParsePKCS8PrivateKey(der, v...)
+	if err != nil {
+		return nil, err
+	}
+	typedKey, ok := key.(*SM2PrivateKey)
+	if !ok {
+		return nil, errors.New("key block is not of type SM2")
+	}
+	return typedKey, nil
+}
@@ -10,7 +10,6 @@ import (
 	"crypto/x509/pkix"
 	"encoding/asn1"
 	"errors"
-	"fmt"
 )
 
 // DefaultOpts are the default options for encrypting a key if none are given.
@@ -67,6 +66,20 @@ type Cipher interface {
 	OID() asn1.ObjectIdentifier
 }
 
+// ParameterizedCipher is a Cipher that encodes its own AlgorithmIdentifier
+// parameters rather than a bare OCTET STRING IV, for example a nonce and tag
+// length. Ciphers passed to RegisterCipher may optionally implement it.
+type ParameterizedCipher interface {
+	Cipher
+	// MarshalParameters returns the DER-encoded cipher parameters for the given IV.
+	MarshalParameters(iv []byte) ([]byte, error)
+	// UnmarshalParameters decodes DER-encoded cipher parameters.
+	// It returns the cipher as configured by the parameters and the IV.
+	// An *UnsupportedAlgorithmError is returned to the caller as is, other
+	// errors are reported as a MalformedInputError.
+	UnmarshalParameters(der []byte) (Cipher, []byte, error)
+}
+
 var ciphers = make(map[string]func() Cipher)
 
 // RegisterCipher registers a function that returns a new instance of the given
@@ -79,6 +92,9 @@ func RegisterCipher(oid asn1.ObjectIdentifier, cipher func() Cipher) {
 type Opts struct {
 	Cipher  Cipher
 	KDFOpts KDFOpts
+	// PBE selects a legacy PBES1 or PKCS#12 scheme instead of PBES2, in which
+	// case Cipher and KDFOpts are ignored.
+	PBE *PBEOpts
 }
 
 var (
@@ -90,6 +106,13 @@ type encryptedPrivateKeyInfo struct {
 	EncryptedData       []byte
 }
 
+// privateKeyInfo is the PrivateKeyInfo structure from RFC 5208.
+type privateKeyInfo struct {
+	Version    int
+	Algo       pkix.AlgorithmIdentifier
+	PrivateKey []byte
+}
+
 type pbes2Params struct {
 	KeyDerivationFunc pkix.AlgorithmIdentifier
 	EncryptionScheme  pkix.AlgorithmIdentifier
@@ -110,12 +133,12 @@ func parseKeyDerivationFunc(keyDerivationFunc pkix.AlgorithmIdentifier) (KDFPara
 	oid := keyDerivationFunc.Algorithm.String()
 	newParams, ok := kdfs[oid]
 	if !ok {
-		return nil, fmt.Errorf("pkcs8: unsupported KDF (OID: %s)", oid)
+		return nil, &UnsupportedAlgorithmError{Kind: "KDF", OID: keyDerivationFunc.Algorithm}
 	}
 	params := newParams()
 	err := unmarshal(keyDerivationFunc.Parameters.FullBytes, params)
 	if err != nil {
-		return nil, errors.New("pkcs8: invalid KDF parameters")
+		return nil, &MalformedInputError{Field: "KDF parameters", Err: err}
 	}
 	return params, nil
 }
@@ -124,56 +147,183 @@ func parseEncryptionScheme(encryptionScheme pkix.AlgorithmIdentifier) (Cipher, [
 	oid := encryptionScheme.Algorithm.String()
 	newCipher, ok := ciphers[oid]
 	if !ok {
-		return nil, nil, fmt.Errorf("pkcs8: unsupported cipher (OID: %s)", oid)
+		return nil, nil, &UnsupportedAlgorithmError{Kind: "cipher", OID: encryptionScheme.Algorithm}
 	}
 	cipher := newCipher()
+	if pc, ok := cipher.(ParameterizedCipher); ok {
+		configured, iv, err := pc.UnmarshalParameters(encryptionScheme.Parameters.FullBytes)
+		if err != nil {
+			if _, ok := err.(*UnsupportedAlgorithmError); ok {
+				return nil, nil, err
+			}
+			return nil, nil, &MalformedInputError{Field: "cipher parameters", Err: err}
+		}
+		return configured, iv, nil
+	}
 	var iv []byte
 	if err := unmarshal(encryptionScheme.Parameters.FullBytes, &iv); err != nil {
-		return nil, nil, errors.New("pkcs8: invalid cipher parameters")
+		return nil, nil, &MalformedInputError{Field: "cipher parameters", Err: err}
 	}
 	return cipher, iv, nil
 }
 
+func marshalEncryptionScheme(cipher Cipher, iv []byte) (pkix.AlgorithmIdentifier, error) {
+	var marshalledParams []byte
+	var err error
+	if pc, ok := cipher.(ParameterizedCipher); ok {
+		marshalledParams, err = pc.MarshalParameters(iv)
+	} else {
+		marshalledParams, err = asn1.Marshal(iv)
+	}
+	if err != nil {
+		return pkix.AlgorithmIdentifier{}, err
+	}
+	return pkix.AlgorithmIdentifier{
+		Algorithm:  cipher.OID(),
+		Parameters: asn1.RawValue{FullBytes: marshalledParams},
+	}, nil
+}
+
+// x509KeyAlgorithms are the key algorithms parsed by crypto/x509.
+var x509KeyAlgorithms = []asn1.ObjectIdentifier{
+	{1, 2, 840, 113549, 1, 1, 1}, // rsaEncryption
+	oidPublicKeyECDSA,
+	{1, 3, 101, 110}, // X25519
+	{1, 3, 101, 112}, // Ed25519
+}
+
+// parsePKCS8PrivateKey parses an unencrypted PKCS#8 private key, handling the
+// key types not supported by crypto/x509.
+func parsePKCS8PrivateKey(der []byte) (interface{}, error) {
+	var info privateKeyInfo
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, &MalformedInputError{Field: "PrivateKeyInfo", Err: err}
+	}
+	switch {
+	case isSM2PrivateKeyInfo(info):
+		return parseSM2PrivateKey(info.PrivateKey)
+	case isGOSTPrivateKeyInfo(info):
+		return parseGOSTPrivateKey(info)
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		for _, oid := range x509KeyAlgorithms {
+			if info.Algo.Algorithm.Equal(oid) {
+				return nil, &MalformedInputError{Field: "private key", Err: err}
+			}
+		}
+		return nil, &UnsupportedAlgorithmError{Kind: "key algorithm", OID: info.Algo.Algorithm}
+	}
+	return key, nil
+}
+
+// marshalPKCS8PrivateKey converts a private key to unencrypted PKCS#8, handling
+// the key types not supported by crypto/x509.
+func marshalPKCS8PrivateKey(priv interface{}) ([]byte, error) {
+	switch k := priv.(type) {
+	case *SM2PrivateKey:
+		return marshalSM2PrivateKey(k)
+	case *GOSTPrivateKey:
+		return marshalGOSTPrivateKey(k)
+	}
+	return x509.MarshalPKCS8PrivateKey(priv)
+}
+
 // ParsePrivateKey parses a DER-encoded PKCS#8 private key.
-// Password can be nil.
+// The password is ignored if the key is unencrypted, and is required if it is
+// encrypted. An incorrect password results in ErrIncorrectPassword.
 // This is equivalent to ParsePKCS8PrivateKey.
 func ParsePrivateKey(der []byte, password []byte) (interface{}, KDFParameters, error) {
-	// No password provided, assume the private key is unencrypted
-	if len(password) == 0 {
-		privateKey, err := x509.ParsePKCS8PrivateKey(der)
+	if !isEncryptedPrivateKeyInfo(der) {
+		privateKey, err := parsePKCS8PrivateKey(der)
 		return privateKey, nil, err
 	}
 
-	// Use the password provided to decrypt the private key
-	var privKey encryptedPrivateKeyInfo
-	if err := unmarshal(der, &privKey); err != nil {
-		return nil, nil, errors.New("pkcs8: only PKCS #5 v2.0 supported")
+	if len(password) == 0 {
+		return nil, nil, ErrPasswordRequired
 	}
+	return DecryptPrivateKey(der, password)
+}
 
-	var decryptedKey []byte
-	var kdfParams KDFParameters
-	var err error
-	if privKey.EncryptionAlgorithm.Algorithm.Equal(oidPBES2) {
-		decryptedKey, kdfParams, err = decryptPBES2(privKey, password)
-	} else {
-		decryptedKey, kdfParams, err = decryptPBE(privKey, password)
-	}
+// DecryptPrivateKey parses a DER-encoded encrypted PKCS#8 private key.
+// Unlike ParsePrivateKey, an unencrypted key is rejected, and an empty or nil
+// password is used to decrypt the key.
+func DecryptPrivateKey(der []byte, password []byte) (interface{}, KDFParameters, error) {
+	decryptedKey, kdfParams, err := decryptPrivateKeyInfo(der, password)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	key, err := x509.ParsePKCS8PrivateKey(decryptedKey)
+	// A PrivateKeyInfo that does not decode means the password was incorrect,
+	// but one that does was decrypted correctly even if it cannot be parsed.
+	var info privateKeyInfo
+	if _, err := asn1.Unmarshal(decryptedKey, &info); err != nil {
+		return nil, nil, ErrIncorrectPassword
+	}
+	key, err := parsePKCS8PrivateKey(decryptedKey)
 	if err != nil {
-		return nil, nil, errors.New("pkcs8: incorrect password")
+		return nil, nil, err
 	}
 	return key, kdfParams, nil
 }
 
+// ParsePrivateKeyWithInfo parses a DER-encoded PKCS#8 private key like
+// ParsePrivateKey, and also returns how the key was protected as described by
+// Inspect. KeyInfo.Opts can be used to re-encrypt the key with the same
+// protection.
+func ParsePrivateKeyWithInfo(der []byte, password []byte) (interface{}, *KeyInfo, error) {
+	info, err := Inspect(der)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, _, err := ParsePrivateKey(der, password)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, info, nil
+}
+
+// isEncryptedPrivateKeyInfo reports whether der is an EncryptedPrivateKeyInfo
+// rather than a PrivateKeyInfo.
+func isEncryptedPrivateKeyInfo(der []byte) bool {
+	var privKey encryptedPrivateKeyInfo
+	return unmarshal(der, &privKey) == nil
+}
+
+// decryptPrivateKeyInfo decrypts a DER-encoded EncryptedPrivateKeyInfo and
+// returns the DER-encoded PrivateKeyInfo.
+func decryptPrivateKeyInfo(der []byte, password []byte) ([]byte, KDFParameters, error) {
+	var privKey encryptedPrivateKeyInfo
+	if err := unmarshal(der, &privKey); err != nil {
+		var info privateKeyInfo
+		if unmarshal(der, &info) == nil {
+			return nil, nil, ErrNotEncrypted
+		}
+		return nil, nil, &MalformedInputError{Field: "EncryptedPrivateKeyInfo", Err: err}
+	}
+
+	if privKey.EncryptionAlgorithm.Algorithm.Equal(oidPBES2) {
+		return decryptPBES2(privKey, password)
+	}
+	return decryptPBE(privKey, password)
+}
+
+// kdfError reports an error from deriving a key while decrypting as malformed
+// KDF parameters, unless it is already typed.
+func kdfError(err error) error {
+	switch err.(type) {
+	case *MalformedInputError, *UnsupportedAlgorithmError:
+		return err
+	}
+	return &MalformedInputError{Field: "KDF parameters", Err: err}
+}
+
 func decryptPBES2(privKey encryptedPrivateKeyInfo, password []byte) ([]byte, KDFParameters, error) {
 	var params pbes2Params
 	err := unmarshal(privKey.EncryptionAlgorithm.Parameters.FullBytes, &params)
 	if err != nil {
-		return nil, nil, errors.New("pkcs8: invalid PBES2 parameters")
+		return nil, nil, &MalformedInputError{Field: "PBES2 parameters", Err: err}
 	}
 
 	cipherType, iv, err := parseEncryptionScheme(params.EncryptionScheme)
@@ -189,7 +339,7 @@ func decryptPBES2(privKey encryptedPrivateKeyInfo, password []byte) ([]byte, KDF
 	keySize := cipherType.KeySize()
 	symKey, err := kdfParams.DeriveKey(password, keySize)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, kdfError(err)
 	}
 
 	encryptedKey := privKey.EncryptedData
@@ -205,22 +355,39 @@ func decryptPBES2(privKey encryptedPrivateKeyInfo, password []byte) ([]byte, KDF
 // Password can be nil.
 func MarshalPrivateKey(priv interface{}, password []byte, opts *Opts) ([]byte, error) {
 	if len(password) == 0 {
-		return x509.MarshalPKCS8PrivateKey(priv)
+		return marshalPKCS8PrivateKey(priv)
 	}
 
-	if opts == nil {
-		opts = DefaultOpts
-	}
+	return EncryptPrivateKey(priv, password, opts)
+}
 
+// EncryptPrivateKey encodes a private key into DER-encoded encrypted PKCS#8
+// with the given options. Unlike MarshalPrivateKey, the key is encrypted even
+// if the password is empty or nil.
+func EncryptPrivateKey(priv interface{}, password []byte, opts *Opts) ([]byte, error) {
 	// Convert private key into PKCS8 format
-	pkey, err := x509.MarshalPKCS8PrivateKey(priv)
+	pkey, err := marshalPKCS8PrivateKey(priv)
 	if err != nil {
 		return nil, err
 	}
 
+	return encryptPrivateKeyInfo(pkey, password, opts)
+}
+
+// encryptPrivateKeyInfo encrypts a DER-encoded PrivateKeyInfo into a
+// DER-encoded EncryptedPrivateKeyInfo with the given options.
+func encryptPrivateKeyInfo(pkey, password []byte, opts *Opts) ([]byte, error) {
+	if opts == nil {
+		opts = DefaultOpts
+	}
+
+	if opts.PBE != nil {
+		return encryptPBE(pkey, password, opts.PBE)
+	}
+
 	encAlg := opts.Cipher
 	salt := make([]byte, opts.KDFOpts.GetSaltSize())
-	_, err = rand.Read(salt)
+	_, err := rand.Read(salt)
 	if err != nil {
 		return nil, err
 	}
@@ -247,14 +414,10 @@ func MarshalPrivateKey(priv interface{}, password []byte, opts *Opts) ([]byte, e
 		Algorithm:  opts.KDFOpts.OID(),
 		Parameters: asn1.RawValue{FullBytes: marshalledParams},
 	}
-	marshalledIV, err := asn1.Marshal(iv)
+	encryptionScheme, err := marshalEncryptionScheme(encAlg, iv)
 	if err != nil {
 		return nil, err
 	}
-	encryptionScheme := pkix.AlgorithmIdentifier{
-		Algorithm:  encAlg.OID(),
-		Parameters: asn1.RawValue{FullBytes: marshalledIV},
-	}
 
 	encryptionAlgorithmParams := pbes2Params{
 		EncryptionScheme:  encryptionScheme,
@@ -277,6 +440,62 @@ func MarshalPrivateKey(priv interface{}, password []byte, opts *Opts) ([]byte, e
 	return asn1.Marshal(encryptedPkey)
 }
 
+// ChangePassword re-encrypts a DER-encoded PKCS#8 private key with a new
+// password and the given options. The PrivateKeyInfo is re-encrypted as is
+// rather than parsed, so keys of any algorithm and their attributes are
+// preserved byte for byte.
+// As with ParsePrivateKey, the old password is ignored if the key is
+// unencrypted. The new password can be nil for an unencrypted output.
+func ChangePassword(der, oldPassword, newPassword []byte, opts *Opts) ([]byte, error) {
+	encrypted := isEncryptedPrivateKeyInfo(der)
+	if encrypted && len(oldPassword) == 0 {
+		return nil, ErrPasswordRequired
+	}
+	pkey, err := changePasswordPrivateKeyInfo(der, oldPassword, encrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(newPassword) == 0 {
+		return pkey, nil
+	}
+	return encryptPrivateKeyInfo(pkey, newPassword, opts)
+}
+
+// ReencryptPrivateKey re-encrypts a DER-encoded encrypted PKCS#8 private key
+// like ChangePassword. As with DecryptPrivateKey and EncryptPrivateKey, an
+// unencrypted key is rejected, and empty or nil passwords are used to decrypt
+// and encrypt the key.
+func ReencryptPrivateKey(der, oldPassword, newPassword []byte, opts *Opts) ([]byte, error) {
+	pkey, err := changePasswordPrivateKeyInfo(der, oldPassword, true)
+	if err != nil {
+		return nil, err
+	}
+	return encryptPrivateKeyInfo(pkey, newPassword, opts)
+}
+
+// changePasswordPrivateKeyInfo returns the DER-encoded PrivateKeyInfo of der,
+// decrypting it with password if encrypted is set.
+func changePasswordPrivateKeyInfo(der, password []byte, encrypted bool) ([]byte, error) {
+	pkey := der
+	if encrypted {
+		var err error
+		pkey, _, err = decryptPrivateKeyInfo(der, password)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var info privateKeyInfo
+	if err := unmarshal(pkey, &info); err != nil {
+		if encrypted {
+			return nil, ErrIncorrectPassword
+		}
+		return nil, &MalformedInputError{Field: "PrivateKeyInfo", Err: err}
+	}
+	return pkey, nil
+}
+
 // ParsePKCS8PrivateKey parses encrypted/unencrypted private keys in PKCS#8 format. To parse encrypted private keys, a password of []byte type should be provided to the function as the second parameter.
 func ParsePKCS8PrivateKey(der []byte, v ...[]byte) (interface{}, error) {
 	var password []byte
@@ -316,7 +535,7 @@ func ParsePKCS8PrivateKeyECDSA(der []byte, v ...[]byte) (*ecdsa.PrivateKey, erro
 // ConvertPrivateKeyToPKCS8 converts the private key into PKCS#8 format.
 // To encrypt the private key, the password of []byte type should be provided as the second parameter.
 //
-// The only supported key types are RSA and ECDSA (*rsa.PrivateKey or *ecdsa.PrivateKey for priv)
+// The only supported key types are RSA, ECDSA, SM2 and GOST (*rsa.PrivateKey, *ecdsa.PrivateKey, *SM2PrivateKey or *GOSTPrivateKey for priv)
 func ConvertPrivateKeyToPKCS8(priv interface{}, v ...[]byte) ([]byte, error) {
 	var password []byte
 	if len(v) > 0 {